@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/jamesc159/monmetrics/configs"
+	"github.com/jamesc159/monmetrics/internal/aggregator"
+	"github.com/jamesc159/monmetrics/internal/database"
+	"github.com/jamesc159/monmetrics/internal/outliers"
+)
+
+func main() {
+	backfill := flag.Bool("backfill", false, "rebuild candles for the --from/--to range instead of running incrementally")
+	fromStr := flag.String("from", "", "backfill start date (YYYY-MM-DD), defaults to 5 years ago")
+	toStr := flag.String("to", "", "backfill end date (YYYY-MM-DD, inclusive), defaults to today")
+	cardStr := flag.String("card", "", "limit backfill to a single card ID")
+	flag.Parse()
+
+	// Load configuration
+	config := configs.Load()
+
+	// Initialize database
+	db, err := database.Connect(config.MongoURI, config.DBName)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer database.Disconnect()
+
+	agg := aggregator.New(db)
+	detector := outliers.New(db, config.OutlierWindow, config.OutlierThreshold)
+	ctx := context.Background()
+	start := time.Now()
+
+	// Flag suspect sales before building candles so they are left out
+	fmt.Println("🚩 Scoring sales for outliers...")
+	flagged, err := detector.Run(ctx)
+	if err != nil {
+		log.Fatalf("Outlier detection failed: %v", err)
+	}
+	fmt.Printf("🚩 Scored %d sales across %d cards, %d flagged (%d changed)\n", flagged.Scored, flagged.Cards, flagged.Flagged, flagged.Changed)
+
+	var result aggregator.Result
+	if *backfill {
+		now := time.Now().UTC()
+		from, err := parseDate(*fromStr, now.AddDate(-5, 0, 0))
+		if err != nil {
+			log.Fatalf("Invalid --from date: %v", err)
+		}
+		to, err := parseDate(*toStr, now)
+		if err != nil {
+			log.Fatalf("Invalid --to date: %v", err)
+		}
+
+		var cardID *primitive.ObjectID
+		if *cardStr != "" {
+			id, err := primitive.ObjectIDFromHex(*cardStr)
+			if err != nil {
+				log.Fatalf("Invalid --card ID: %v", err)
+			}
+			cardID = &id
+		}
+
+		fmt.Printf("🔁 Backfilling market data from %s to %s...\n", from.Format("2006-01-02"), to.Format("2006-01-02"))
+		result, err = agg.Backfill(ctx, from, to, cardID)
+		if err != nil {
+			log.Fatalf("Backfill failed: %v", err)
+		}
+	} else {
+		if _, err := agg.Rebuild(ctx, flagged.Dirty); err != nil {
+			log.Fatalf("Rebuilding flagged days failed: %v", err)
+		}
+
+		fmt.Println("📊 Aggregating new price data...")
+		result, err = agg.Run(ctx)
+		if err != nil {
+			log.Fatalf("Aggregation failed: %v", err)
+		}
+	}
+
+	fmt.Printf("✅ Wrote %d daily candles for %d cards in %v\n", result.Candles, result.Cards, time.Since(start).Round(time.Millisecond))
+}
+
+// parseDate parses a YYYY-MM-DD flag value, returning fallback when empty
+func parseDate(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
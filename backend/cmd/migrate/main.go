@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jamesc159/monmetrics/configs"
+	"github.com/jamesc159/monmetrics/internal/database"
+	"github.com/jamesc159/monmetrics/internal/variants"
+)
+
+func main() {
+	// Load configuration
+	config := configs.Load()
+
+	// Initialize database
+	db, err := database.Connect(config.MongoURI, config.DBName)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer database.Disconnect()
+
+	start := time.Now()
+	fmt.Println("🧬 Moving existing cards, prices, listings and charts onto default variants...")
+
+	result, err := variants.Migrate(context.Background(), db)
+	if err != nil {
+		log.Fatalf("Variant migration failed: %v", err)
+	}
+
+	fmt.Printf("✅ Added %d default variants; assigned %d prices, %d listings and %d saved charts in %v\n",
+		result.Cards, result.Prices, result.Listings, result.Charts, time.Since(start).Round(time.Millisecond))
+}
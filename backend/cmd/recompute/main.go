@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jamesc159/monmetrics/configs"
+	"github.com/jamesc159/monmetrics/internal/database"
+	"github.com/jamesc159/monmetrics/internal/pricestats"
+)
+
+func main() {
+	quiet := flag.Bool("quiet", false, "only print the summary line, not each changed card")
+	flag.Parse()
+
+	// Load configuration
+	config := configs.Load()
+
+	// Initialize database
+	db, err := database.Connect(config.MongoURI, config.DBName)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer database.Disconnect()
+
+	start := time.Now()
+	fmt.Println("🧮 Recomputing current price and ATH/ATL for the whole catalog...")
+
+	result, err := pricestats.New(db).Run(context.Background())
+	if err != nil {
+		log.Fatalf("Recompute failed: %v", err)
+	}
+
+	if !*quiet {
+		for _, c := range result.Changed {
+			fmt.Printf("  • %s (%s)\n", c.Name, c.CardID.Hex())
+			printField("current", c.Before.CurrentPrice, c.After.CurrentPrice, time.Time{}, time.Time{})
+			printField("ATH", c.Before.AllTimeHigh, c.After.AllTimeHigh, c.Before.ATHDate, c.After.ATHDate)
+			printField("ATL", c.Before.AllTimeLow, c.After.AllTimeLow, c.Before.ATLDate, c.After.ATLDate)
+		}
+	}
+
+	fmt.Printf("✅ %d of %d cards changed (%d without price history) in %v\n",
+		len(result.Changed), result.Cards, result.Skipped, time.Since(start).Round(time.Millisecond))
+}
+
+// printField prints one summary field, marking it when it changed
+func printField(label string, before, after float64, beforeDate, afterDate time.Time) {
+	marker := " "
+	if before != after || !beforeDate.Equal(afterDate) {
+		marker = "*"
+	}
+
+	line := fmt.Sprintf("    %s %-8s $%.2f → $%.2f", marker, label, before, after)
+	if !afterDate.IsZero() {
+		line += fmt.Sprintf(" (%s → %s)", formatDate(beforeDate), formatDate(afterDate))
+	}
+	fmt.Println(line)
+}
+
+// formatDate renders a date, or "-" when unset
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02")
+}
@@ -13,7 +13,9 @@ import (
 
 	"github.com/jamesc159/monmetrics/configs"
 	"github.com/jamesc159/monmetrics/internal/database"
+	"github.com/jamesc159/monmetrics/internal/fx"
 	"github.com/jamesc159/monmetrics/internal/models"
+	"github.com/jamesc159/monmetrics/internal/variants"
 )
 
 // ═══════════════════════════════════════════════════════════════════════════════
@@ -47,6 +49,51 @@ func getRandomSource() string {
 	return sources[rand.Intn(len(sources))]
 }
 
+// scarletVioletBoosterBox returns the contents of a 36-pack Scarlet & Violet
+// booster box. Pull rates are approximate community-tracked odds.
+func scarletVioletBoosterBox() *models.SealedContents {
+	return &models.SealedContents{
+		PacksPerBox: 36,
+		Slots: []models.PackSlot{
+			{Name: "common", Count: 4, PullRates: []models.PullRate{{Rarity: "Common", Rate: 1}}},
+			{Name: "uncommon", Count: 3, PullRates: []models.PullRate{{Rarity: "Uncommon", Rate: 1}}},
+			{Name: "reverse holo", Count: 1, PullRates: []models.PullRate{
+				{Rarity: "Common", Rate: 0.55}, {Rarity: "Uncommon", Rate: 0.3}, {Rarity: "Rare", Rate: 0.15},
+			}},
+			{Name: "foil", Count: 1, PullRates: []models.PullRate{
+				{Rarity: "Common", Rate: 0.5}, {Rarity: "Uncommon", Rate: 0.3}, {Rarity: "Rare", Rate: 0.07},
+				{Rarity: "Illustration Rare", Rate: 0.08}, {Rarity: "Special Illustration Rare", Rate: 0.03},
+				{Rarity: "Hyper Rare", Rate: 0.02},
+			}},
+			{Name: "rare", Count: 1, PullRates: []models.PullRate{
+				{Rarity: "Rare", Rate: 0.78}, {Rarity: "Double Rare", Rate: 0.15}, {Rarity: "Ultra Rare", Rate: 0.07},
+			}},
+		},
+		UpdatedAt: time.Now(),
+	}
+}
+
+// swordShieldBoosterBox returns the contents of a 36-pack Sword & Shield
+// booster box. Pull rates are approximate community-tracked odds.
+func swordShieldBoosterBox() *models.SealedContents {
+	return &models.SealedContents{
+		PacksPerBox: 36,
+		Slots: []models.PackSlot{
+			{Name: "common", Count: 5, PullRates: []models.PullRate{{Rarity: "Common", Rate: 1}}},
+			{Name: "uncommon", Count: 3, PullRates: []models.PullRate{{Rarity: "Uncommon", Rate: 1}}},
+			{Name: "reverse holo", Count: 1, PullRates: []models.PullRate{
+				{Rarity: "Common", Rate: 0.6}, {Rarity: "Uncommon", Rate: 0.3}, {Rarity: "Rare", Rate: 0.1},
+			}},
+			{Name: "rare", Count: 1, PullRates: []models.PullRate{
+				{Rarity: "Rare", Rate: 0.42}, {Rarity: "Rare Holo", Rate: 0.28}, {Rarity: "V", Rate: 0.16},
+				{Rarity: "VMAX", Rate: 0.07}, {Rarity: "Full Art", Rate: 0.04}, {Rarity: "Alternate Art", Rate: 0.02},
+				{Rarity: "Rainbow Rare", Rate: 0.01},
+			}},
+		},
+		UpdatedAt: time.Now(),
+	}
+}
+
 // timePtr returns a pointer to a time.Time value
 func timePtr(t time.Time) *time.Time {
 	return &t
@@ -1006,6 +1053,7 @@ func main() {
 			Number:         "",
 			ImageURL:       "https://images.pokemontcg.io/sv4/logo.png",
 			Description:    "Scarlet & Violet Paradox Rift booster box - 36 packs",
+			Contents:       scarletVioletBoosterBox(),
 			CurrentPrice:   125.00,
 			AllTimeHigh:    165.00,
 			AllTimeLow:     95.00,
@@ -1026,6 +1074,7 @@ func main() {
 			Number:         "",
 			ImageURL:       "https://images.pokemontcg.io/sv3/logo.png",
 			Description:    "Scarlet & Violet Obsidian Flames booster box - 36 packs",
+			Contents:       scarletVioletBoosterBox(),
 			CurrentPrice:   110.00,
 			AllTimeHigh:    145.00,
 			AllTimeLow:     88.00,
@@ -1106,6 +1155,7 @@ func main() {
 			Number:         "",
 			ImageURL:       "https://images.pokemontcg.io/swsh7/logo.png",
 			Description:    "Highly sought Evolving Skies booster box with Eeveelutions - 36 packs",
+			Contents:       swordShieldBoosterBox(),
 			CurrentPrice:   245.00,
 			AllTimeHigh:    385.00,
 			AllTimeLow:     165.00,
@@ -1688,6 +1738,7 @@ func main() {
 					Source:    source,
 					Timestamp: currentDate,
 					CreatedAt: currentDate,
+					Condition: "Near Mint",
 				}
 				prices = append(prices, pricePoint)
 			}
@@ -1769,6 +1820,29 @@ func main() {
 		}
 	}
 
+	// Put every card, price and listing on a default printing variant
+	fmt.Println("🧬 Assigning default variants...")
+	migration, err := variants.Migrate(ctx, db)
+	if err != nil {
+		log.Printf("Warning: Failed to assign default variants: %v", err)
+	} else {
+		fmt.Printf("   ✅ %d cards, %d prices and %d listings on default variants\n", migration.Cards, migration.Prices, migration.Listings)
+	}
+
+	// Load FX rates so prices can be shown in other currencies
+	if config.FXRatesFile != "" {
+		fmt.Println("💱 Loading FX rates...")
+		rates, err := fx.ReadFile(config.FXRatesFile)
+		if err == nil {
+			_, err = fx.Save(ctx, db, rates)
+		}
+		if err != nil {
+			log.Printf("Warning: Failed to load FX rates: %v", err)
+		} else {
+			fmt.Printf("   ✅ %d FX rates loaded from %s\n", len(rates), config.FXRatesFile)
+		}
+	}
+
 	// Create text search indexes for better performance
 	fmt.Println("🔍 Creating database indexes for optimal performance...")
 	createSearchIndexes(ctx, db)
@@ -10,10 +10,24 @@ import (
 	"syscall"
 	"time"
 
+	"go.mongodb.org/mongo-driver/mongo"
+
 	"github.com/jamesc159/monmetrics/configs"
+	"github.com/jamesc159/monmetrics/internal/aggregator"
+	"github.com/jamesc159/monmetrics/internal/conditions"
 	"github.com/jamesc159/monmetrics/internal/database"
+	"github.com/jamesc159/monmetrics/internal/fairvalue"
+	"github.com/jamesc159/monmetrics/internal/fx"
 	"github.com/jamesc159/monmetrics/internal/handlers"
+	"github.com/jamesc159/monmetrics/internal/indices"
+	"github.com/jamesc159/monmetrics/internal/ingest"
+	"github.com/jamesc159/monmetrics/internal/liquidity"
 	"github.com/jamesc159/monmetrics/internal/middleware"
+	"github.com/jamesc159/monmetrics/internal/movers"
+	"github.com/jamesc159/monmetrics/internal/outliers"
+	"github.com/jamesc159/monmetrics/internal/popularity"
+	"github.com/jamesc159/monmetrics/internal/pricestats"
+	"github.com/jamesc159/monmetrics/internal/scheduler"
 )
 
 func main() {
@@ -27,8 +41,20 @@ func main() {
 	}
 	defer database.Disconnect()
 
-	// Initialize handlers
-	h := handlers.New(db, config)
+	// Initialize handlers; card views are buffered and written once a minute
+	views := popularity.NewViews(db)
+	h := handlers.New(db, config, views)
+
+	// Load FX rates from the configured file; rates can also be posted to the admin API
+	if config.FXRatesFile != "" {
+		loadFXRates(db, config.FXRatesFile)
+	}
+
+	// Start background jobs; they stop when the server shuts down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	startBackgroundJobs(jobsCtx, db, config)
+	views.Run(jobsCtx, popularity.ViewFlushInterval)
 
 	// Setup router with middleware
 	mux := http.NewServeMux()
@@ -41,12 +67,28 @@ func main() {
 	apiMux.HandleFunc("GET /cards/search", h.SearchCards)
 	apiMux.HandleFunc("GET /cards/{id}", h.GetCard)
 	apiMux.HandleFunc("GET /cards/{id}/prices", h.GetCardPrices)
+	apiMux.HandleFunc("GET /cards/{id}/spread", h.GetCardSpread)
+	apiMux.HandleFunc("GET /cards/{id}/stats", h.GetCardStats)
+	apiMux.HandleFunc("GET /cards/{id}/correlations", h.GetCardCorrelations)
+	apiMux.HandleFunc("GET /cards/{id}/forecast", h.GetCardForecast)
+	apiMux.HandleFunc("GET /cards/{id}/grades", h.GetGradeLadder)
+	apiMux.HandleFunc("GET /cards/{id}/grading-roi", h.GetGradingROI)
+	apiMux.HandleFunc("GET /cards/{id}/fair-value", h.GetCardFairValue)
+	apiMux.HandleFunc("GET /cards/{id}/ev", h.GetCardEV)
 
 	// Featured content and organized search
 	apiMux.HandleFunc("GET /featured-content", h.GetFeaturedContent)
 	apiMux.HandleFunc("GET /cards/by-game", h.GetCardsByGame)
 	apiMux.HandleFunc("GET /sealed/by-game", h.GetSealedByGame)
 
+	// Market benchmark indices
+	apiMux.HandleFunc("GET /indices", h.GetIndices)
+	apiMux.HandleFunc("GET /indices/{id}/history", h.GetIndexHistory)
+
+	// Market-wide rankings
+	apiMux.HandleFunc("GET /market/leaderboards", h.GetLeaderboards)
+	apiMux.HandleFunc("GET /market/deals", h.GetDeals)
+
 	// Auth routes (public)
 	apiMux.HandleFunc("POST /auth/register", h.Register)
 	apiMux.HandleFunc("POST /auth/login", h.Login)
@@ -58,6 +100,12 @@ func main() {
 	protectedMux.HandleFunc("POST /user/charts", h.SaveChart)
 	protectedMux.HandleFunc("GET /user/charts", h.GetSavedCharts)
 	protectedMux.HandleFunc("DELETE /user/charts/{id}", h.DeleteChart)
+	protectedMux.HandleFunc("POST /user/charts/{id}/backtest", h.BacktestChart)
+
+	// Admin routes (require ADMIN_API_KEY)
+	adminMux := http.NewServeMux()
+	adminMux.HandleFunc("POST /fx-rates", h.ImportFXRates)
+	adminMux.HandleFunc("PUT /cards/{id}/contents", h.SetProductContents)
 
 	// Apply middleware stack to public API routes
 	api := middleware.Chain(
@@ -65,6 +113,7 @@ func main() {
 		middleware.SecurityHeaders(),
 		middleware.RateLimit(config.RateLimitRequests, config.RateLimitWindow),
 		middleware.RequestLogger(),
+		middleware.OptionalAuth(config.JWTSecret),
 	)(apiMux)
 
 	// Apply middleware stack to protected routes (includes auth)
@@ -76,9 +125,18 @@ func main() {
 		middleware.AuthRequired(config.JWTSecret),
 	)(protectedMux)
 
+	// Apply middleware stack to admin routes (requires the admin key)
+	adminAPI := middleware.Chain(
+		middleware.SecurityHeaders(),
+		middleware.RateLimit(config.RateLimitRequests, config.RateLimitWindow),
+		middleware.RequestLogger(),
+		middleware.AdminKey(config.AdminAPIKey),
+	)(adminMux)
+
 	// Mount routes
 	mux.Handle("/api/", http.StripPrefix("/api", api))
 	mux.Handle("/api/protected/", http.StripPrefix("/api/protected", protectedAPI))
+	mux.Handle("/api/admin/", http.StripPrefix("/api/admin", adminAPI))
 
 	// Create HTTP server
 	server := &http.Server{
@@ -110,9 +168,21 @@ func main() {
 	fmt.Printf("🔍 Search Cards:     GET  http://localhost:%s/api/cards/search\n", config.Port)
 	fmt.Printf("📋 Get Card:         GET  http://localhost:%s/api/cards/{id}\n", config.Port)
 	fmt.Printf("📈 Card Prices:      GET  http://localhost:%s/api/cards/{id}/prices\n", config.Port)
+	fmt.Printf("↔️  Price Spread:     GET  http://localhost:%s/api/cards/{id}/spread\n", config.Port)
+	fmt.Printf("📉 Risk Stats:       GET  http://localhost:%s/api/cards/{id}/stats\n", config.Port)
+	fmt.Printf("🔗 Correlations:     GET  http://localhost:%s/api/cards/{id}/correlations\n", config.Port)
+	fmt.Printf("🔮 Forecast:         GET  http://localhost:%s/api/cards/{id}/forecast\n", config.Port)
+	fmt.Printf("🪜 Grade Ladder:     GET  http://localhost:%s/api/cards/{id}/grades\n", config.Port)
+	fmt.Printf("🧾 Grading ROI:      GET  http://localhost:%s/api/cards/{id}/grading-roi\n", config.Port)
+	fmt.Printf("⚖️  Fair Value:       GET  http://localhost:%s/api/cards/{id}/fair-value\n", config.Port)
+	fmt.Printf("🎁 Sealed EV:        GET  http://localhost:%s/api/cards/{id}/ev\n", config.Port)
 	fmt.Printf("🎪 Featured Content: GET  http://localhost:%s/api/featured-content\n", config.Port)
 	fmt.Printf("🎮 Cards by Game:    GET  http://localhost:%s/api/cards/by-game\n", config.Port)
 	fmt.Printf("📦 Sealed by Game:   GET  http://localhost:%s/api/sealed/by-game\n", config.Port)
+	fmt.Printf("🏛️  Indices:          GET  http://localhost:%s/api/indices\n", config.Port)
+	fmt.Printf("📜 Index History:    GET  http://localhost:%s/api/indices/{id}/history\n", config.Port)
+	fmt.Printf("🏆 Leaderboards:     GET  http://localhost:%s/api/market/leaderboards\n", config.Port)
+	fmt.Printf("🏷️  Deals:            GET  http://localhost:%s/api/market/deals\n", config.Port)
 	fmt.Printf("👤 Register:         POST http://localhost:%s/api/auth/register\n", config.Port)
 	fmt.Printf("🔑 Login:            POST http://localhost:%s/api/auth/login\n", config.Port)
 	fmt.Printf("🚪 Logout:           POST http://localhost:%s/api/auth/logout\n", config.Port)
@@ -121,6 +191,10 @@ func main() {
 	fmt.Printf("💾 Save Chart:       POST http://localhost:%s/api/protected/user/charts\n", config.Port)
 	fmt.Printf("📋 Get Charts:       GET  http://localhost:%s/api/protected/user/charts\n", config.Port)
 	fmt.Printf("🗑️  Delete Chart:     DEL  http://localhost:%s/api/protected/user/charts/{id}\n", config.Port)
+	fmt.Printf("🧪 Backtest Chart:   POST http://localhost:%s/api/protected/user/charts/{id}/backtest\n", config.Port)
+	fmt.Println("\n🛡️  Admin API (requires X-Admin-Key):")
+	fmt.Printf("💱 Import FX Rates:  POST http://localhost:%s/api/admin/fx-rates\n", config.Port)
+	fmt.Printf("📦 Product Contents: PUT  http://localhost:%s/api/admin/cards/{id}/contents\n", config.Port)
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Printf("🎯 Frontend URL:     http://localhost:3000\n")
 	fmt.Println("\n✅ Server is ready to accept connections!")
@@ -131,6 +205,7 @@ func main() {
 	<-quit
 
 	log.Println("🛑 Shutting down server...")
+	stopJobs()
 
 	// Create a deadline for shutdown
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -142,4 +217,204 @@ func main() {
 	} else {
 		log.Println("✅ Server gracefully stopped")
 	}
+
+	// Write the views recorded since the last flush
+	if _, err := views.Flush(ctx); err != nil {
+		log.Printf("⚠️  Could not record card views: %v", err)
+	}
+}
+
+// startBackgroundJobs schedules the periodic data maintenance jobs
+func startBackgroundJobs(ctx context.Context, db *mongo.Database, config *configs.Config) {
+	if config.AggregationInterval > 0 {
+		agg := aggregator.New(db)
+		detector := outliers.New(db, config.OutlierWindow, config.OutlierThreshold)
+		updater := pricestats.New(db)
+		scheduler.Every(ctx, "market data aggregation", config.AggregationInterval, func(ctx context.Context) error {
+			// Flag suspect sales first so they never reach the daily candles
+			flagged, err := detector.Run(ctx)
+			if err != nil {
+				return err
+			}
+			if _, err := agg.Rebuild(ctx, flagged.Dirty); err != nil {
+				return err
+			}
+			log.Printf("🚩 Scored %d sales, %d flagged as outliers (%d changed)", flagged.Scored, flagged.Flagged, flagged.Changed)
+
+			result, err := agg.Run(ctx)
+			if err != nil {
+				return err
+			}
+			log.Printf("📊 Aggregated %d daily candles across %d cards", result.Candles, result.Cards)
+
+			// Keep the denormalized current price and ATH/ATL in step with the new data
+			summaries, err := updater.Run(ctx)
+			if err != nil {
+				return err
+			}
+			log.Printf("🧮 Updated price summaries on %d of %d cards", len(summaries.Changed), summaries.Cards)
+			return nil
+		})
+	}
+
+	if config.IndexInterval > 0 {
+		builder := indices.New(db, config.IndexWeighting, config.IndexRebalance)
+		scheduler.Every(ctx, "market index computation", config.IndexInterval, func(ctx context.Context) error {
+			result, err := builder.Run(ctx)
+			if err != nil {
+				return err
+			}
+			log.Printf("🏛️  Computed %d values across %d indices", result.Values, result.Indices)
+			return nil
+		})
+	}
+
+	if config.MoversInterval > 0 {
+		windows, err := movers.ParseWindows(config.MoversWindows)
+		if err != nil {
+			log.Printf("⚠️  Market movers disabled: %v", err)
+		} else {
+			publisher := movers.NewPublisher(db, windows, config.MoversMinVolume, config.MoversLimit, config.MoversTTL)
+			scheduler.Every(ctx, "market movers", config.MoversInterval, func(ctx context.Context) error {
+				result, err := publisher.Run(ctx)
+				if err != nil {
+					return err
+				}
+				log.Printf("🚀 Published %d market movers, retired %d", result.Published, result.Retired)
+				return nil
+			})
+		}
+	}
+
+	if config.PopularityInterval > 0 {
+		ranker := popularity.New(db, popularity.Weights{
+			Sales:    config.PopularityWeightSales,
+			Listings: config.PopularityWeightListings,
+			Views:    config.PopularityWeightViews,
+		})
+		scheduler.Every(ctx, "popularity ranking", config.PopularityInterval, func(ctx context.Context) error {
+			result, err := ranker.Run(ctx)
+			if err != nil {
+				return err
+			}
+			log.Printf("⭐ Ranked %d cards in %d groups, %d ranks changed", result.Cards, result.Groups, result.Changed)
+			return nil
+		})
+	}
+
+	if config.LiquidityInterval > 0 {
+		calculator := liquidity.New(db, config.LiquidityWindowDays)
+		scheduler.Every(ctx, "liquidity metrics", config.LiquidityInterval, func(ctx context.Context) error {
+			result, err := calculator.Run(ctx)
+			if err != nil {
+				return err
+			}
+			log.Printf("💧 Updated liquidity metrics on %d cards", result.Cards)
+			return nil
+		})
+	}
+
+	if config.ConditionInterval > 0 {
+		estimator := conditions.NewEstimator(db)
+		scheduler.Every(ctx, "condition multipliers", config.ConditionInterval, func(ctx context.Context) error {
+			result, err := estimator.Run(ctx)
+			if err != nil {
+				return err
+			}
+			log.Printf("🏷️  Estimated %d condition multipliers across %d games (%d from observed data)", result.Multipliers, result.Games, result.Observed)
+			return nil
+		})
+	}
+
+	if config.FairValueInterval > 0 {
+		calculator := fairvalue.New(db, fairvalue.Params{
+			WindowDays:   config.FairValueWindowDays,
+			HalfLifeDays: config.FairValueHalfLifeDays,
+		})
+		scheduler.Every(ctx, "fair value", config.FairValueInterval, func(ctx context.Context) error {
+			result, err := calculator.Run(ctx)
+			if err != nil {
+				return err
+			}
+			log.Printf("⚖️  Estimated fair value for %d of %d cards", result.Estimated, result.Cards)
+			return nil
+		})
+	}
+
+	if config.IngestInterval > 0 {
+		sources, err := openIngestSources(config)
+		if err != nil {
+			log.Printf("⚠️  Marketplace ingestion disabled: %v", err)
+			return
+		}
+
+		runner := ingest.NewRunner(db, ingest.Options{
+			MaxRetries: config.IngestMaxRetries,
+			Lookback:   time.Duration(config.IngestLookbackDays) * 24 * time.Hour,
+		})
+		agg := aggregator.New(db)
+		detector := outliers.New(db, config.OutlierWindow, config.OutlierThreshold)
+		updater := pricestats.New(db)
+		scheduler.Every(ctx, "marketplace ingestion", config.IngestInterval, func(ctx context.Context) error {
+			for _, source := range sources {
+				result, err := runner.Run(ctx, source)
+				if err != nil {
+					return err
+				}
+				log.Printf("📥 Ingested %s: %d new sales, %d listings stored, %d removed, %d unmapped", result.Source, result.Sales, result.Listings, result.Removed, result.Unmapped)
+
+				// Sales can land on days that already have candles; screen them
+				// for outliers before they reach market_data
+				settled, err := ingest.Settle(ctx, detector, agg, updater, result.Dirty)
+				if err != nil {
+					return err
+				}
+				if len(result.Dirty) > 0 {
+					log.Printf("🚩 Settled %d cards: %d outliers flagged, %d candles rebuilt, %d price summaries updated", len(result.Dirty), settled.Flagged, settled.Candles, settled.Updated)
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// openIngestSources opens the marketplaces listed in INGEST_SOURCES
+func openIngestSources(config *configs.Config) ([]ingest.PriceSource, error) {
+	names, err := ingest.ParseSources(config.IngestSources)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("INGEST_SOURCES is empty")
+	}
+
+	sources := make([]ingest.PriceSource, 0, len(names))
+	for _, name := range names {
+		baseURL, token := config.IngestEndpoint(name)
+		source, err := ingest.Open(name, ingest.Endpoint{BaseURL: baseURL, Token: token}, config.IngestFixturesDir)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
+
+// loadFXRates upserts the rates in a CSV or JSON file into fx_rates
+func loadFXRates(db *mongo.Database, path string) {
+	rates, err := fx.ReadFile(path)
+	if err != nil {
+		log.Printf("⚠️  Could not read FX rates: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	saved, err := fx.Save(ctx, db, rates)
+	if err != nil {
+		log.Printf("⚠️  Could not load FX rates: %v", err)
+		return
+	}
+	log.Printf("💱 Loaded %d FX rates from %s (%d new or changed)", len(rates), path, saved)
 }
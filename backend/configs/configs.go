@@ -16,6 +16,71 @@ type Config struct {
 	Environment        string
 	RateLimitRequests  int
 	RateLimitWindow    time.Duration
+
+	// Background jobs (0 disables the job)
+	AggregationInterval time.Duration
+	IndexInterval       time.Duration
+	MoversInterval      time.Duration
+	PopularityInterval  time.Duration
+	LiquidityInterval   time.Duration
+	ConditionInterval   time.Duration
+	FairValueInterval   time.Duration
+
+	// Market index defaults for newly discovered indices
+	IndexWeighting string // "price" or "equal"
+	IndexRebalance string // "daily", "weekly" or "monthly"
+
+	// Outlier detection: prior same-source sales compared against and the
+	// robust z-score above which a sale is flagged
+	OutlierWindow    int
+	OutlierThreshold float64
+
+	// Market movers featured in the carousel
+	MoversWindows   string // Comma-separated lookbacks, e.g. "24h,7d,30d"
+	MoversMinVolume int    // Minimum sales in the window to qualify
+	MoversLimit     int    // Gainers and losers featured per window
+	MoversTTL       time.Duration
+
+	// Popularity rank weights for 6-month sales, active listings and page views
+	PopularityWeightSales    float64
+	PopularityWeightListings float64
+	PopularityWeightViews    float64
+
+	// Sales lookback for sell-through and days-to-sell
+	LiquidityWindowDays int
+
+	// Fair value: sales lookback and the sale age at which its weight halves
+	FairValueWindowDays   int
+	FairValueHalfLifeDays float64
+
+	// Grading ROI defaults: fee per card and grade probabilities ("10:0.1,9:0.4,...")
+	GradingFee           float64
+	GradingProbabilities string
+
+	// Deal finder: fee overrides ("source:rate:fixed:shipping,...") and the
+	// fair value confidence required to compare listings against it
+	DealsFees          string
+	DealsMinConfidence float64
+
+	// Marketplace ingestion: sources read on a schedule, each API's endpoint
+	// and token, and recorded responses to read instead (<dir>/<source>)
+	IngestInterval     time.Duration
+	IngestSources      string // Comma-separated, e.g. "ebay,tcgplayer,cardmarket"
+	IngestFixturesDir  string
+	IngestLookbackDays int // Sales history read on a source's first run
+	IngestMaxRetries   int
+	EBayAPIURL         string
+	EBayAPIToken       string
+	TCGplayerAPIURL    string
+	TCGplayerAPIToken  string
+	CardmarketAPIURL   string
+	CardmarketAPIToken string
+
+	// FX rates loaded into fx_rates at startup (CSV or JSON; empty skips loading)
+	FXRatesFile string
+
+	// Key required in the X-Admin-Key header for admin endpoints (empty disables them)
+	AdminAPIKey string
 }
 
 func Load() *Config {
@@ -44,12 +109,88 @@ func Load() *Config {
 	}
 	config.RateLimitWindow = rateLimitWindow
 
+	config.AggregationInterval = getDurationEnv("AGGREGATION_INTERVAL", time.Hour)
+	config.IndexInterval = getDurationEnv("INDEX_INTERVAL", 24*time.Hour)
+	config.IndexWeighting = getEnv("INDEX_WEIGHTING", "price")
+	config.IndexRebalance = getEnv("INDEX_REBALANCE", "monthly")
+	config.OutlierWindow = getIntEnv("OUTLIER_WINDOW", 30)
+	config.OutlierThreshold = getFloatEnv("OUTLIER_THRESHOLD", 3.5)
+	config.MoversInterval = getDurationEnv("MOVERS_INTERVAL", time.Hour)
+	config.MoversWindows = getEnv("MOVERS_WINDOWS", "24h,7d,30d")
+	config.MoversMinVolume = getIntEnv("MOVERS_MIN_VOLUME", 5)
+	config.MoversLimit = getIntEnv("MOVERS_LIMIT", 3)
+	config.MoversTTL = getDurationEnv("MOVERS_TTL", 24*time.Hour)
+	config.PopularityInterval = getDurationEnv("POPULARITY_INTERVAL", 24*time.Hour)
+	config.PopularityWeightSales = getFloatEnv("POPULARITY_WEIGHT_SALES", 0.5)
+	config.PopularityWeightListings = getFloatEnv("POPULARITY_WEIGHT_LISTINGS", 0.2)
+	config.PopularityWeightViews = getFloatEnv("POPULARITY_WEIGHT_VIEWS", 0.3)
+	config.LiquidityInterval = getDurationEnv("LIQUIDITY_INTERVAL", 6*time.Hour)
+	config.LiquidityWindowDays = getIntEnv("LIQUIDITY_WINDOW_DAYS", 30)
+	config.ConditionInterval = getDurationEnv("CONDITION_INTERVAL", 24*time.Hour)
+	config.FairValueInterval = getDurationEnv("FAIR_VALUE_INTERVAL", 6*time.Hour)
+	config.FairValueWindowDays = getIntEnv("FAIR_VALUE_WINDOW_DAYS", 90)
+	config.FairValueHalfLifeDays = getFloatEnv("FAIR_VALUE_HALF_LIFE_DAYS", 14)
+	config.GradingFee = getFloatEnv("GRADING_FEE", 25)
+	config.GradingProbabilities = getEnv("GRADING_PROBABILITIES", "10:0.1,9:0.4,8:0.3,7:0.1")
+	config.DealsFees = getEnv("DEALS_FEES", "")
+	config.DealsMinConfidence = getFloatEnv("DEALS_MIN_CONFIDENCE", 0.3)
+	config.IngestInterval = getDurationEnv("INGEST_INTERVAL", 0)
+	config.IngestSources = getEnv("INGEST_SOURCES", "")
+	config.IngestFixturesDir = getEnv("INGEST_FIXTURES_DIR", "")
+	config.IngestLookbackDays = getIntEnv("INGEST_LOOKBACK_DAYS", 30)
+	config.IngestMaxRetries = getIntEnv("INGEST_MAX_RETRIES", 3)
+	config.EBayAPIURL = getEnv("EBAY_API_URL", "https://api.ebay.com")
+	config.EBayAPIToken = getEnv("EBAY_API_TOKEN", "")
+	config.TCGplayerAPIURL = getEnv("TCGPLAYER_API_URL", "https://api.tcgplayer.com")
+	config.TCGplayerAPIToken = getEnv("TCGPLAYER_API_TOKEN", "")
+	config.CardmarketAPIURL = getEnv("CARDMARKET_API_URL", "https://api.cardmarket.com")
+	config.CardmarketAPIToken = getEnv("CARDMARKET_API_TOKEN", "")
+	config.FXRatesFile = getEnv("FX_RATES_FILE", "")
+	config.AdminAPIKey = getEnv("ADMIN_API_KEY", "")
+
 	return config
 }
 
+// IngestEndpoint returns a marketplace's API base URL and token
+func (c *Config) IngestEndpoint(source string) (string, string) {
+	switch source {
+	case "ebay":
+		return c.EBayAPIURL, c.EBayAPIToken
+	case "tcgplayer":
+		return c.TCGplayerAPIURL, c.TCGplayerAPIToken
+	case "cardmarket":
+		return c.CardmarketAPIURL, c.CardmarketAPIToken
+	}
+	return "", ""
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
+}
+
+func getIntEnv(key string, defaultValue int) int {
+	value, err := strconv.Atoi(getEnv(key, strconv.Itoa(defaultValue)))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getFloatEnv(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, strconv.FormatFloat(defaultValue, 'f', -1, 64)), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, defaultValue.String()))
+	if err != nil {
+		return defaultValue
+	}
+	return value
 }
\ No newline at end of file
@@ -0,0 +1,224 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/conditions"
+	"github.com/jamesc159/monmetrics/internal/fx"
+	"github.com/jamesc159/monmetrics/internal/models"
+	"github.com/jamesc159/monmetrics/internal/variants"
+)
+
+// Aggregator rolls raw price points up into daily market_data documents
+type Aggregator struct {
+	db *mongo.Database
+}
+
+// Result summarizes an aggregation run
+type Result struct {
+	Cards   int `json:"cards"`
+	Candles int `json:"candles"`
+}
+
+// New creates a new Aggregator instance
+func New(db *mongo.Database) *Aggregator {
+	return &Aggregator{db: db}
+}
+
+// Run incrementally aggregates every card that has prices. Each card resumes
+// from its latest market_data day, which is rebuilt in case it was partial.
+func (a *Aggregator) Run(ctx context.Context) (Result, error) {
+	var result Result
+
+	cardIDs, err := a.cardIDs(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	now := time.Now().UTC()
+	for _, cardID := range cardIDs {
+		from, err := a.lastAggregatedDate(ctx, cardID)
+		if err != nil {
+			return result, err
+		}
+
+		n, err := a.AggregateCard(ctx, cardID, from, now)
+		if err != nil {
+			return result, err
+		}
+		result.Cards++
+		result.Candles += n
+	}
+
+	return result, nil
+}
+
+// Backfill rebuilds daily candles for the inclusive date range [from, to].
+// When cardID is nil every card with prices is processed.
+func (a *Aggregator) Backfill(ctx context.Context, from, to time.Time, cardID *primitive.ObjectID) (Result, error) {
+	var result Result
+
+	if to.Before(from) {
+		return result, fmt.Errorf("backfill end %s is before start %s", to.Format("2006-01-02"), from.Format("2006-01-02"))
+	}
+
+	var cardIDs []primitive.ObjectID
+	if cardID != nil {
+		cardIDs = []primitive.ObjectID{*cardID}
+	} else {
+		var err error
+		if cardIDs, err = a.cardIDs(ctx); err != nil {
+			return result, err
+		}
+	}
+
+	// Include the whole of the last day
+	end := TruncateDay(to).AddDate(0, 0, 1)
+	for _, id := range cardIDs {
+		n, err := a.AggregateCard(ctx, id, from, end)
+		if err != nil {
+			return result, err
+		}
+		result.Cards++
+		result.Candles += n
+	}
+
+	return result, nil
+}
+
+// Rebuild re-aggregates each card from the given time onward, e.g. after the
+// outlier detector changed which of its sales count
+func (a *Aggregator) Rebuild(ctx context.Context, since map[primitive.ObjectID]time.Time) (Result, error) {
+	var result Result
+
+	now := time.Now().UTC()
+	for cardID, from := range since {
+		n, err := a.AggregateCard(ctx, cardID, from, now)
+		if err != nil {
+			return result, err
+		}
+		result.Cards++
+		result.Candles += n
+	}
+
+	return result, nil
+}
+
+// AggregateCard builds daily candles for prices in [from, to) and upserts them
+// keyed by (card_id, date), so re-running over the same range is idempotent.
+// Existing candles in the range for days that no longer have sales are removed.
+// Candles track raw Near Mint copies of the card's default variant: other printings,
+// played copies, graded sales and sales flagged as outliers are left out.
+func (a *Aggregator) AggregateCard(ctx context.Context, cardID primitive.ObjectID, from, to time.Time) (int, error) {
+	var card models.Card
+	err := a.db.Collection("cards").FindOne(ctx, bson.M{"_id": cardID}, options.FindOne().SetProjection(bson.M{"variants": 1})).Decode(&card)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return 0, fmt.Errorf("failed to load variants for card %s: %v", cardID.Hex(), err)
+	}
+
+	filter := bson.M{
+		"card_id":    cardID,
+		"variant_id": variants.MatchDefault(card),
+		"timestamp": bson.M{
+			"$gte": TruncateDay(from),
+			"$lt":  to,
+		},
+		"outlier":   bson.M{"$ne": true},
+		"grader":    bson.M{"$in": bson.A{nil, ""}},
+		"condition": conditions.MatchNearMint(),
+	}
+
+	cursor, err := a.db.Collection("prices").Find(ctx, filter, options.Find().SetSort(bson.M{"timestamp": 1}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load prices for card %s: %v", cardID.Hex(), err)
+	}
+	defer cursor.Close(ctx)
+
+	var prices []models.PricePoint
+	if err := cursor.All(ctx, &prices); err != nil {
+		return 0, fmt.Errorf("failed to decode prices for card %s: %v", cardID.Hex(), err)
+	}
+
+	// Candles are stored in USD whatever currency each marketplace quotes in
+	if err := fx.ToBase(ctx, a.db, prices); err != nil {
+		return 0, fmt.Errorf("failed to convert prices for card %s: %v", cardID.Hex(), err)
+	}
+
+	candles := BuildCandles(cardID, prices, TruncateDay)
+
+	// Days left without qualifying sales (e.g. every sale was flagged as an
+	// outlier or moved to another variant) must not keep serving old candles
+	days := make(bson.A, 0, len(candles))
+	for _, candle := range candles {
+		days = append(days, candle.Date)
+	}
+	_, err = a.db.Collection("market_data").DeleteMany(ctx, bson.M{
+		"card_id": cardID,
+		"date": bson.M{
+			"$gte": TruncateDay(from),
+			"$lt":  to,
+			"$nin": days,
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove stale market data for card %s: %v", cardID.Hex(), err)
+	}
+
+	if len(candles) == 0 {
+		return 0, nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(candles))
+	for _, candle := range candles {
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"card_id": candle.CardID, "date": candle.Date}).
+			SetReplacement(candle).
+			SetUpsert(true))
+	}
+
+	_, err = a.db.Collection("market_data").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("failed to write market data for card %s: %v", cardID.Hex(), err)
+	}
+
+	return len(candles), nil
+}
+
+// cardIDs returns every card that has at least one price point
+func (a *Aggregator) cardIDs(ctx context.Context) ([]primitive.ObjectID, error) {
+	values, err := a.db.Collection("prices").Distinct(ctx, "card_id", bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list priced cards: %v", err)
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// lastAggregatedDate returns the latest market_data day for a card, or the zero time
+func (a *Aggregator) lastAggregatedDate(ctx context.Context, cardID primitive.ObjectID) (time.Time, error) {
+	var latest models.MarketData
+	err := a.db.Collection("market_data").FindOne(ctx,
+		bson.M{"card_id": cardID},
+		options.FindOne().SetSort(bson.M{"date": -1}),
+	).Decode(&latest)
+	if err == mongo.ErrNoDocuments {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to find latest market data for card %s: %v", cardID.Hex(), err)
+	}
+	return latest.Date, nil
+}
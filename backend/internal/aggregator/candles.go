@@ -0,0 +1,107 @@
+package aggregator
+
+import (
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// TruncateDay returns midnight UTC of the day containing t
+func TruncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// TruncateHour returns the start of the UTC hour containing t
+func TruncateHour(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Hour)
+}
+
+// TruncateWeek returns midnight UTC of the Monday starting the week containing t
+func TruncateWeek(t time.Time) time.Time {
+	day := TruncateDay(t)
+	offset := (int(day.Weekday()) + 6) % 7 // Monday = 0
+	return day.AddDate(0, 0, -offset)
+}
+
+// TruncateMonth returns midnight UTC of the first day of the month containing t
+func TruncateMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// BucketFunc returns the truncation function for a candle interval (1h, 1d, 1w, 1M)
+func BucketFunc(interval string) (func(time.Time) time.Time, bool) {
+	switch interval {
+	case "1h":
+		return TruncateHour, true
+	case "1d":
+		return TruncateDay, true
+	case "1w":
+		return TruncateWeek, true
+	case "1M":
+		return TruncateMonth, true
+	default:
+		return nil, false
+	}
+}
+
+// BuildCandles groups prices (sorted by timestamp ascending) into OHLC candles.
+// The bucket function maps a timestamp to the start of its candle.
+func BuildCandles(cardID primitive.ObjectID, prices []models.PricePoint, bucket func(time.Time) time.Time) []models.MarketData {
+	candles := make([]models.MarketData, 0)
+
+	var (
+		current     *models.MarketData
+		weightedSum float64
+		priceSum    float64
+		count       int
+	)
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		// Fall back to a simple mean when no volume was reported
+		if current.Volume > 0 {
+			current.WeightedAvgPrice = roundCents(weightedSum / float64(current.Volume))
+		} else {
+			current.WeightedAvgPrice = roundCents(priceSum / float64(count))
+		}
+		candles = append(candles, *current)
+	}
+
+	for _, p := range prices {
+		start := bucket(p.Timestamp)
+		if current == nil || !current.Date.Equal(start) {
+			flush()
+			current = &models.MarketData{
+				CardID:    cardID,
+				Date:      start,
+				OpenPrice: p.Price,
+				HighPrice: p.Price,
+				LowPrice:  p.Price,
+			}
+			weightedSum, priceSum, count = 0, 0, 0
+		}
+
+		current.ClosePrice = p.Price
+		current.HighPrice = math.Max(current.HighPrice, p.Price)
+		current.LowPrice = math.Min(current.LowPrice, p.Price)
+		current.Volume += p.Volume
+		weightedSum += p.Price * float64(p.Volume)
+		priceSum += p.Price
+		count++
+	}
+	flush()
+
+	return candles
+}
+
+// roundCents rounds a price to two decimal places
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,115 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MinOverlap is the minimum number of aligned returns needed for a correlation
+const MinOverlap = 20
+
+// AlignedReturns computes log returns for two series over the dates both share,
+// so a missing day in either series never pairs returns from different spans
+func AlignedReturns(a, b []Point) ([]float64, []float64) {
+	bByDate := make(map[time.Time]float64, len(b))
+	for _, p := range b {
+		bByDate[p.Date] = p.Value
+	}
+
+	var alignedA, alignedB []Point
+	for _, p := range a {
+		if v, ok := bByDate[p.Date]; ok {
+			alignedA = append(alignedA, p)
+			alignedB = append(alignedB, Point{Date: p.Date, Value: v})
+		}
+	}
+
+	return LogReturns(alignedA), LogReturns(alignedB)
+}
+
+// Correlation returns the Pearson correlation coefficient of x and y
+func Correlation(x, y []float64) float64 {
+	n := len(x)
+	if n != len(y) || n < 2 {
+		return 0
+	}
+
+	meanX, meanY := Mean(x), Mean(y)
+	var cov, varX, varY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// Beta returns the sensitivity of asset returns to market returns (cov / var)
+func Beta(asset, market []float64) float64 {
+	n := len(asset)
+	if n != len(market) || n < 2 {
+		return 0
+	}
+
+	meanA, meanM := Mean(asset), Mean(market)
+	var cov, varM float64
+	for i := range asset {
+		dm := market[i] - meanM
+		cov += (asset[i] - meanA) * dm
+		varM += dm * dm
+	}
+	if varM == 0 {
+		return 0
+	}
+	return cov / varM
+}
+
+// MarketSeries builds an equal-weighted market level from several close series.
+// Each day's level moves by the average log return of the cards priced on both
+// that day and the previous market day.
+func MarketSeries(series [][]Point) []Point {
+	byDate := make(map[time.Time][]float64)
+	closesByCard := make([]map[time.Time]float64, len(series))
+	dateSet := make(map[time.Time]bool)
+	for i, s := range series {
+		closesByCard[i] = make(map[time.Time]float64, len(s))
+		for _, p := range s {
+			closesByCard[i][p.Date] = p.Value
+			dateSet[p.Date] = true
+		}
+	}
+
+	dates := make([]time.Time, 0, len(dateSet))
+	for d := range dateSet {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	for i := 1; i < len(dates); i++ {
+		for _, closes := range closesByCard {
+			cur, okCur := closes[dates[i]]
+			prev, okPrev := closes[dates[i-1]]
+			if okCur && okPrev && prev > 0 && cur > 0 {
+				byDate[dates[i]] = append(byDate[dates[i]], math.Log(cur/prev))
+			}
+		}
+	}
+
+	if len(dates) == 0 {
+		return nil
+	}
+	level := 1.0
+	points := []Point{{Date: dates[0], Value: level}}
+	for _, d := range dates[1:] {
+		if returns := byDate[d]; len(returns) > 0 {
+			level *= math.Exp(Mean(returns))
+		}
+		points = append(points, Point{Date: d, Value: level})
+	}
+	return points
+}
@@ -0,0 +1,172 @@
+package analytics
+
+import (
+	"math"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// TradingDaysPerYear is used to annualize daily statistics. Card marketplaces
+// trade every day, so this is a calendar year rather than 252 exchange days.
+const TradingDaysPerYear = 365
+
+// Point is a single dated value in a daily series
+type Point struct {
+	Date  time.Time
+	Value float64
+}
+
+// ClosesFromMarketData extracts the daily closing price series
+func ClosesFromMarketData(data []models.MarketData) []Point {
+	points := make([]Point, 0, len(data))
+	for _, d := range data {
+		if d.ClosePrice > 0 {
+			points = append(points, Point{Date: d.Date, Value: d.ClosePrice})
+		}
+	}
+	return points
+}
+
+// LogReturns returns ln(p[i]/p[i-1]) for consecutive points
+func LogReturns(points []Point) []float64 {
+	if len(points) < 2 {
+		return nil
+	}
+	returns := make([]float64, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		returns = append(returns, math.Log(points[i].Value/points[i-1].Value))
+	}
+	return returns
+}
+
+// AnnualizedVolatility scales the standard deviation of daily log returns to a year
+func AnnualizedVolatility(returns []float64) float64 {
+	return StdDev(returns) * math.Sqrt(TradingDaysPerYear)
+}
+
+// MaxDrawdown finds the largest peak-to-trough decline in the series
+func MaxDrawdown(points []Point) models.Drawdown {
+	var result models.Drawdown
+	if len(points) == 0 {
+		return result
+	}
+
+	peak := points[0]
+	for _, p := range points[1:] {
+		if p.Value > peak.Value {
+			peak = p
+			continue
+		}
+
+		drawdown := (p.Value - peak.Value) / peak.Value * 100
+		if drawdown < result.Pct {
+			result = models.Drawdown{
+				Pct:         round(drawdown, 4),
+				PeakDate:    peak.Date,
+				PeakPrice:   peak.Value,
+				TroughDate:  p.Date,
+				TroughPrice: p.Value,
+			}
+		}
+	}
+
+	// A drawdown has recovered once a later close regains the prior peak
+	if result.Pct < 0 {
+		for _, p := range points {
+			if p.Date.After(result.TroughDate) && p.Value >= result.PeakPrice {
+				result.Recovered = true
+				break
+			}
+		}
+	}
+
+	return result
+}
+
+// PeriodReturn returns the percentage change from the last close on or before
+// (latest - days) to the latest close. ok is false when history is too short.
+func PeriodReturn(points []Point, days int) (float64, bool) {
+	if len(points) < 2 {
+		return 0, false
+	}
+	latest := points[len(points)-1]
+	cutoff := latest.Date.AddDate(0, 0, -days)
+
+	for i := len(points) - 2; i >= 0; i-- {
+		if !points[i].Date.After(cutoff) {
+			return round((latest.Value-points[i].Value)/points[i].Value*100, 4), true
+		}
+	}
+	return 0, false
+}
+
+// RiskAdjustedRatios returns Sharpe-like and Sortino-like ratios from daily
+// log returns, assuming a zero risk-free rate
+func RiskAdjustedRatios(returns []float64) (annualReturn, sharpe, sortino float64) {
+	if len(returns) < 2 {
+		return 0, 0, 0
+	}
+
+	annualReturn = Mean(returns) * TradingDaysPerYear
+	if vol := AnnualizedVolatility(returns); vol > 0 {
+		sharpe = annualReturn / vol
+	}
+
+	// Downside deviation only penalizes negative returns
+	downside := 0.0
+	for _, r := range returns {
+		if r < 0 {
+			downside += r * r
+		}
+	}
+	downsideDev := math.Sqrt(downside/float64(len(returns))) * math.Sqrt(TradingDaysPerYear)
+	if downsideDev > 0 {
+		sortino = annualReturn / downsideDev
+	}
+
+	return annualReturn, sharpe, sortino
+}
+
+// CardStats computes risk statistics for the closes inside [from, to]. The
+// full history is used for period returns so short ranges still report 1y.
+func CardStats(history []Point, from, to time.Time) models.CardStats {
+	var inRange []Point
+	for _, p := range history {
+		if !p.Date.Before(from) && !p.Date.After(to) {
+			inRange = append(inRange, p)
+		}
+	}
+
+	stats := models.CardStats{
+		Observations: len(inRange),
+		Returns:      make(map[string]*float64),
+	}
+
+	returns := LogReturns(inRange)
+	stats.AnnualizedVolatility = round(AnnualizedVolatility(returns)*100, 4)
+	annualReturn, sharpe, sortino := RiskAdjustedRatios(returns)
+	stats.AnnualizedReturn = round(annualReturn*100, 4)
+	stats.SharpeRatio = round(sharpe, 4)
+	stats.SortinoRatio = round(sortino, 4)
+	stats.MaxDrawdown = MaxDrawdown(inRange)
+
+	periods := []struct {
+		label string
+		days  int
+	}{{"1d", 1}, {"7d", 7}, {"30d", 30}, {"90d", 90}, {"1y", 365}}
+	for _, period := range periods {
+		if value, ok := PeriodReturn(history, period.days); ok {
+			v := value
+			stats.Returns[period.label] = &v
+		} else {
+			stats.Returns[period.label] = nil
+		}
+	}
+
+	if len(history) > 0 {
+		stats.CurrentPrice = history[len(history)-1].Value
+	}
+
+	return stats
+}
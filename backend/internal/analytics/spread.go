@@ -0,0 +1,102 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/aggregator"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// DailySourcePrices averages each source's prices per UTC day
+func DailySourcePrices(prices []models.PricePoint) map[time.Time]map[string]float64 {
+	sums := make(map[time.Time]map[string]float64)
+	counts := make(map[time.Time]map[string]int)
+	for _, p := range prices {
+		day := aggregator.TruncateDay(p.Timestamp)
+		if sums[day] == nil {
+			sums[day] = make(map[string]float64)
+			counts[day] = make(map[string]int)
+		}
+		sums[day][p.Source] += p.Price
+		counts[day][p.Source]++
+	}
+
+	for day, bySource := range sums {
+		for source := range bySource {
+			bySource[source] /= float64(counts[day][source])
+		}
+	}
+	return sums
+}
+
+// Spread computes the daily spread between the highest and lowest priced
+// source. Days with fewer than two sources reporting are skipped.
+func Spread(prices []models.PricePoint) models.SpreadAnalysis {
+	daily := DailySourcePrices(prices)
+
+	days := make([]time.Time, 0, len(daily))
+	for day := range daily {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	analysis := models.SpreadAnalysis{
+		Sources: sourcesOf(prices),
+		Days:    make([]models.SpreadPoint, 0, len(days)),
+	}
+
+	var spreadPcts []float64
+	spreadSum := 0.0
+	for _, day := range days {
+		bySource := daily[day]
+		if len(bySource) < 2 {
+			continue
+		}
+
+		point := models.SpreadPoint{Date: day, Prices: make(map[string]float64, len(bySource))}
+		high, low := math.Inf(-1), math.Inf(1)
+		for source, price := range bySource {
+			point.Prices[source] = round(price, 2)
+			if price > high || (price == high && source < point.HighSource) {
+				high, point.HighSource = price, source
+			}
+			if price < low || (price == low && source < point.LowSource) {
+				low, point.LowSource = price, source
+			}
+		}
+
+		point.Spread = round(high-low, 2)
+		if low > 0 {
+			point.SpreadPct = round((high-low)/low*100, 4)
+		}
+
+		analysis.Days = append(analysis.Days, point)
+		spreadSum += high - low
+		spreadPcts = append(spreadPcts, point.SpreadPct)
+	}
+
+	if n := len(analysis.Days); n > 0 {
+		analysis.MeanSpread = round(spreadSum/float64(n), 2)
+		mean := Mean(spreadPcts)
+		analysis.MeanSpreadPct = round(mean, 4)
+		analysis.SpreadVolatility = round(StdDev(spreadPcts), 4)
+	}
+
+	return analysis
+}
+
+// sourcesOf returns the distinct sources present in prices, sorted
+func sourcesOf(prices []models.PricePoint) []string {
+	seen := make(map[string]bool)
+	sources := make([]string, 0)
+	for _, p := range prices {
+		if !seen[p.Source] {
+			seen[p.Source] = true
+			sources = append(sources, p.Source)
+		}
+	}
+	sort.Strings(sources)
+	return sources
+}
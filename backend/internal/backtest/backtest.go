@@ -0,0 +1,234 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/analytics"
+	"github.com/jamesc159/monmetrics/internal/indicators"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// PriceKey is the rule operand that refers to the bar's closing price
+const PriceKey = "price"
+
+// DefaultCapital is the starting equity when the request doesn't set one
+const DefaultCapital = 1000
+
+// Validate checks a request's rules and fee settings before any data is loaded
+func Validate(req models.BacktestRequest) error {
+	if len(req.Entry) == 0 || len(req.Exit) == 0 {
+		return fmt.Errorf("at least one entry and one exit rule are required")
+	}
+	for _, rule := range append(append([]models.BacktestRule{}, req.Entry...), req.Exit...) {
+		if rule.Indicator == "" {
+			return fmt.Errorf("rule indicator is required")
+		}
+		switch rule.Operator {
+		case "<", "<=", ">", ">=", "crosses_above", "crosses_below":
+		default:
+			return fmt.Errorf("invalid operator %q: must be one of <, <=, >, >=, crosses_above, crosses_below", rule.Operator)
+		}
+		if (rule.Value == nil) == (rule.CompareTo == "") {
+			return fmt.Errorf("rule on %q must set exactly one of value or compare_to", rule.Indicator)
+		}
+	}
+	if req.InitialCapital < 0 || req.FixedFee < 0 {
+		return fmt.Errorf("initial_capital and fixed_fee must not be negative")
+	}
+	if req.BuyFeePct < 0 || req.BuyFeePct >= 100 || req.SellFeePct < 0 || req.SellFeePct >= 100 {
+		return fmt.Errorf("fee percentages must be between 0 and 100")
+	}
+	return nil
+}
+
+// Run simulates a long-only, all-in strategy on daily closes. Signals are
+// evaluated at each bar's close and filled at that close, with fees charged
+// on both sides. A position still open on the last bar is closed there.
+func Run(bars []indicators.Bar, series map[string][]models.IndicatorPoint, req models.BacktestRequest) (models.BacktestResult, error) {
+	lookup := make(map[string]map[time.Time]float64, len(series)+1)
+	for key, points := range series {
+		values := make(map[time.Time]float64, len(points))
+		for _, p := range points {
+			values[p.Timestamp] = p.Value
+		}
+		lookup[key] = values
+	}
+	closes := make(map[time.Time]float64, len(bars))
+	for _, b := range bars {
+		closes[b.Timestamp] = b.Close
+	}
+	lookup[PriceKey] = closes
+
+	for _, rule := range append(append([]models.BacktestRule{}, req.Entry...), req.Exit...) {
+		for _, key := range []string{rule.Indicator, rule.CompareTo} {
+			if _, ok := lookup[key]; key != "" && !ok {
+				return models.BacktestResult{}, fmt.Errorf("unknown series %q: available series are %s", key, strings.Join(seriesKeys(lookup), ", "))
+			}
+		}
+	}
+
+	capital := req.InitialCapital
+	if capital == 0 {
+		capital = DefaultCapital
+	}
+
+	result := models.BacktestResult{
+		InitialCapital: capital,
+		Trades:         make([]models.BacktestTrade, 0),
+		EquityCurve:    make([]models.EquityPoint, 0, len(bars)),
+	}
+
+	cash := capital
+	units := 0.0
+	var open models.BacktestTrade
+	var entryCost float64
+
+	closePosition := func(b indicators.Bar, atEnd bool) {
+		gross := units * b.Close
+		sellFee := gross*req.SellFeePct/100 + req.FixedFee
+		proceeds := gross - sellFee
+
+		open.ExitDate = b.Timestamp
+		open.ExitPrice = b.Close
+		open.Fees = round(open.Fees + sellFee)
+		open.ProfitLoss = round(proceeds - entryCost)
+		open.ReturnPct = round((proceeds - entryCost) / entryCost * 100)
+		open.ClosedAtEnd = atEnd
+		open.HoldingDays = int(b.Timestamp.Sub(open.EntryDate).Hours() / 24)
+
+		result.Trades = append(result.Trades, open)
+		result.TotalFees += sellFee
+		cash = proceeds
+		units = 0
+	}
+
+	for i, b := range bars {
+		switch {
+		case units == 0 && b.Close > 0 && allHold(req.Entry, lookup, bars, i):
+			// Spend all cash: notional plus percentage and flat fees
+			notional := (cash - req.FixedFee) / (1 + req.BuyFeePct/100)
+			if notional <= 0 {
+				break
+			}
+			buyFee := cash - notional
+			units = notional / b.Close
+			entryCost = cash
+			open = models.BacktestTrade{EntryDate: b.Timestamp, EntryPrice: b.Close, Fees: buyFee}
+			result.TotalFees += buyFee
+			cash = 0
+		case units > 0 && allHold(req.Exit, lookup, bars, i):
+			closePosition(b, false)
+		}
+
+		result.EquityCurve = append(result.EquityCurve, models.EquityPoint{
+			Date:   b.Timestamp,
+			Equity: round(cash + units*b.Close),
+		})
+	}
+
+	if units > 0 {
+		closePosition(bars[len(bars)-1], true)
+		result.EquityCurve[len(result.EquityCurve)-1].Equity = round(cash)
+	}
+
+	result.FinalEquity = round(cash)
+	result.TotalReturnPct = round((cash - capital) / capital * 100)
+	result.TotalTrades = len(result.Trades)
+	result.TotalFees = round(result.TotalFees)
+
+	wins := 0
+	for _, t := range result.Trades {
+		if t.ProfitLoss > 0 {
+			wins++
+		}
+	}
+	if result.TotalTrades > 0 {
+		result.WinRate = round(float64(wins) / float64(result.TotalTrades) * 100)
+	}
+
+	if len(bars) > 1 && bars[0].Close > 0 {
+		result.BuyAndHoldPct = round((bars[len(bars)-1].Close - bars[0].Close) / bars[0].Close * 100)
+	}
+
+	equity := make([]analytics.Point, len(result.EquityCurve))
+	for i, e := range result.EquityCurve {
+		equity[i] = analytics.Point{Date: e.Date, Value: e.Equity}
+	}
+	result.MaxDrawdown = analytics.MaxDrawdown(equity)
+
+	return result, nil
+}
+
+// allHold reports whether every rule is satisfied at bar i
+func allHold(rules []models.BacktestRule, lookup map[string]map[time.Time]float64, bars []indicators.Bar, i int) bool {
+	for _, rule := range rules {
+		if !holds(rule, lookup, bars, i) {
+			return false
+		}
+	}
+	return true
+}
+
+// holds evaluates a single rule at bar i; missing (warm-up) values never match
+func holds(rule models.BacktestRule, lookup map[string]map[time.Time]float64, bars []indicators.Bar, i int) bool {
+	operand := func(j int) (left, right float64, ok bool) {
+		if j < 0 {
+			return 0, 0, false
+		}
+		ts := bars[j].Timestamp
+		left, ok = lookup[rule.Indicator][ts]
+		if !ok {
+			return 0, 0, false
+		}
+		if rule.Value != nil {
+			return left, *rule.Value, true
+		}
+		right, ok = lookup[rule.CompareTo][ts]
+		return left, right, ok
+	}
+
+	left, right, ok := operand(i)
+	if !ok {
+		return false
+	}
+
+	switch rule.Operator {
+	case "<":
+		return left < right
+	case "<=":
+		return left <= right
+	case ">":
+		return left > right
+	case ">=":
+		return left >= right
+	case "crosses_above", "crosses_below":
+		prevLeft, prevRight, ok := operand(i - 1)
+		if !ok {
+			return false
+		}
+		if rule.Operator == "crosses_above" {
+			return prevLeft <= prevRight && left > right
+		}
+		return prevLeft >= prevRight && left < right
+	}
+	return false
+}
+
+// seriesKeys returns the sorted names of the available series
+func seriesKeys(lookup map[string]map[time.Time]float64) []string {
+	keys := make([]string, 0, len(lookup))
+	for key := range lookup {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// round rounds to cents / hundredths of a percent
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
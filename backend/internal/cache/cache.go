@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// pruneThreshold is the entry count above which Set sweeps expired entries
+const pruneThreshold = 1024
+
+// TTL is a concurrency-safe in-memory cache whose entries expire after a fixed duration
+type TTL[V any] struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]entry[V]
+}
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// NewTTL creates a cache whose entries live for ttl
+func NewTTL[V any](ttl time.Duration) *TTL[V] {
+	return &TTL[V]{
+		ttl:     ttl,
+		entries: make(map[string]entry[V]),
+	}
+}
+
+// Get returns the cached value for key if present and not expired
+func (c *TTL[V]) Get(key string) (V, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key, replacing any existing entry
+func (c *TTL[V]) Set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= pruneThreshold {
+		for k, e := range c.entries {
+			if now.After(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+
+	c.entries[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+}
+
+// Delete removes the entry for key, if any
+func (c *TTL[V]) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
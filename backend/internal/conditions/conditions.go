@@ -0,0 +1,104 @@
+package conditions
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// Card conditions from best to worst, as written on listings
+const (
+	NearMint         = "Near Mint"
+	LightlyPlayed    = "Lightly Played"
+	ModeratelyPlayed = "Moderately Played"
+	HeavilyPlayed    = "Heavily Played"
+	Damaged          = "Damaged"
+)
+
+// All lists every condition from best to worst
+var All = []string{NearMint, LightlyPlayed, ModeratelyPlayed, HeavilyPlayed, Damaged}
+
+// DefaultMultipliers are market-typical prices relative to Near Mint, used
+// until enough observed sales exist for a game
+var DefaultMultipliers = map[string]float64{
+	NearMint:         1.0,
+	LightlyPlayed:    0.85,
+	ModeratelyPlayed: 0.7,
+	HeavilyPlayed:    0.5,
+	Damaged:          0.35,
+}
+
+// aliases maps accepted spellings (lowercase) to conditions
+var aliases = map[string]string{
+	"nm": NearMint, "near_mint": NearMint, "near mint": NearMint, "near-mint": NearMint, "mint": NearMint,
+	"lp": LightlyPlayed, "lightly_played": LightlyPlayed, "lightly played": LightlyPlayed, "lightly-played": LightlyPlayed,
+	"mp": ModeratelyPlayed, "moderately_played": ModeratelyPlayed, "moderately played": ModeratelyPlayed, "moderately-played": ModeratelyPlayed,
+	"hp": HeavilyPlayed, "heavily_played": HeavilyPlayed, "heavily played": HeavilyPlayed, "heavily-played": HeavilyPlayed,
+	"dmg": Damaged, "damaged": Damaged,
+}
+
+// Normalize maps a condition name or abbreviation (e.g. "nm", "lightly_played",
+// "Heavily Played") to its canonical form
+func Normalize(raw string) (string, bool) {
+	condition, ok := aliases[strings.ToLower(strings.TrimSpace(raw))]
+	return condition, ok
+}
+
+// Of returns the condition a sale or listing was recorded with. Sales without
+// a condition are assumed to be Near Mint, which is how marketplaces default.
+func Of(recorded string) string {
+	if condition, ok := Normalize(recorded); ok {
+		return condition
+	}
+	return NearMint
+}
+
+// MatchNearMint returns the condition filter for sales recorded as Near Mint,
+// in any accepted spelling, or without a condition at all
+func MatchNearMint() interface{} {
+	spellings := []string{regexp.QuoteMeta(strings.ToLower(NearMint))}
+	for alias, condition := range aliases {
+		if condition == NearMint && alias != strings.ToLower(NearMint) {
+			spellings = append(spellings, regexp.QuoteMeta(alias))
+		}
+	}
+	sort.Strings(spellings[1:])
+
+	pattern := `^\s*(` + strings.Join(spellings, "|") + `)\s*$`
+	return bson.M{"$in": bson.A{nil, "", primitive.Regex{Pattern: pattern, Options: "i"}}}
+}
+
+// Filter returns the points recorded in the given condition
+func Filter(prices []models.PricePoint, condition string) []models.PricePoint {
+	filtered := make([]models.PricePoint, 0)
+	for _, p := range prices {
+		if Of(p.Condition) == condition {
+			filtered = append(filtered, p)
+		}
+	}
+	return filtered
+}
+
+// Estimate converts every point to the target condition. Points already in
+// that condition are kept as-is; the rest are rescaled by the ratio of the
+// two conditions' multipliers and marked as estimated.
+func Estimate(prices []models.PricePoint, target string, multipliers map[string]models.ConditionMultiplier) []models.PricePoint {
+	estimated := make([]models.PricePoint, 0, len(prices))
+	for _, p := range prices {
+		from := Of(p.Condition)
+		if from != target {
+			ratio := multipliers[target].Multiplier / multipliers[from].Multiplier
+			p.Price = math.Round(p.Price*ratio*100) / 100
+			p.Condition = target
+			p.Estimated = true
+		}
+		estimated = append(estimated, p)
+	}
+	return estimated
+}
@@ -0,0 +1,311 @@
+package conditions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/fx"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+const (
+	// LookbackDays is how far back observations count toward multipliers
+	LookbackDays = 180
+	// MinCards is how many cards need both Near Mint and the condition
+	// observed before the observed multiplier replaces the default
+	MinCards = 3
+)
+
+// Estimator learns per-game condition multipliers from sales, falling back to
+// listing asks and then to DefaultMultipliers when observations are thin
+type Estimator struct {
+	db *mongo.Database
+}
+
+// Result summarizes an estimation run
+type Result struct {
+	Games       int `json:"games"`
+	Multipliers int `json:"multipliers"`
+	Observed    int `json:"observed"` // Multipliers backed by data rather than defaults
+}
+
+// NewEstimator creates a new Estimator instance
+func NewEstimator(db *mongo.Database) *Estimator {
+	return &Estimator{db: db}
+}
+
+// observation is a card variant's median price in one condition
+type observation struct {
+	CardID    primitive.ObjectID
+	VariantID primitive.ObjectID // Nil for data not yet assigned to a variant
+	Condition string
+	Median    float64
+}
+
+// Run recomputes multipliers for every game and upserts them into
+// condition_multipliers keyed by (game, condition)
+func (e *Estimator) Run(ctx context.Context) (Result, error) {
+	var result Result
+	now := time.Now().UTC()
+	since := now.AddDate(0, 0, -LookbackDays)
+
+	games, err := e.cardGames(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	sales, err := e.saleMedians(ctx, since)
+	if err != nil {
+		return result, err
+	}
+	asks, err := e.askMedians(ctx, since, now)
+	if err != nil {
+		return result, err
+	}
+
+	salesRatios := ratios(sales, games)
+	askRatios := ratios(asks, games)
+
+	gameSet := make(map[string]bool)
+	for _, game := range games {
+		gameSet[game] = true
+	}
+
+	var writes []mongo.WriteModel
+	for game := range gameSet {
+		for _, condition := range All {
+			m := models.ConditionMultiplier{
+				Game:       game,
+				Condition:  condition,
+				Multiplier: DefaultMultipliers[condition],
+				Basis:      "default",
+				UpdatedAt:  now,
+			}
+			switch {
+			case condition == NearMint:
+				m.Basis = "reference"
+			case len(salesRatios[game][condition]) >= MinCards:
+				m.Multiplier, m.Samples, m.Basis = round4(median(salesRatios[game][condition])), len(salesRatios[game][condition]), "sales"
+			case len(askRatios[game][condition]) >= MinCards:
+				m.Multiplier, m.Samples, m.Basis = round4(median(askRatios[game][condition])), len(askRatios[game][condition]), "listings"
+			}
+			if m.Basis == "sales" || m.Basis == "listings" {
+				result.Observed++
+			}
+
+			writes = append(writes, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"game": game, "condition": condition}).
+				SetReplacement(m).
+				SetUpsert(true))
+		}
+		result.Games++
+	}
+
+	if len(writes) > 0 {
+		if _, err := e.db.Collection("condition_multipliers").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return result, fmt.Errorf("failed to write condition multipliers: %v", err)
+		}
+	}
+	result.Multipliers = len(writes)
+
+	return result, nil
+}
+
+// ratios returns, per game and condition, each card's median price in that
+// condition divided by its Near Mint median. Printings are compared only
+// against themselves, and cards without a Near Mint observation are skipped.
+func ratios(observations []observation, games map[primitive.ObjectID]string) map[string]map[string][]float64 {
+	type printing struct {
+		cardID    primitive.ObjectID
+		variantID primitive.ObjectID
+	}
+	nearMint := make(map[printing]float64)
+	for _, o := range observations {
+		if o.Condition == NearMint && o.Median > 0 {
+			nearMint[printing{o.CardID, o.VariantID}] = o.Median
+		}
+	}
+
+	byGame := make(map[string]map[string][]float64)
+	for _, o := range observations {
+		base, ok := nearMint[printing{o.CardID, o.VariantID}]
+		game, known := games[o.CardID]
+		if !ok || !known || o.Condition == NearMint {
+			continue
+		}
+		if byGame[game] == nil {
+			byGame[game] = make(map[string][]float64)
+		}
+		byGame[game][o.Condition] = append(byGame[game][o.Condition], o.Median/base)
+	}
+	return byGame
+}
+
+// saleMedians returns each card variant's median sale price per condition
+// since the given time, in the base currency
+func (e *Estimator) saleMedians(ctx context.Context, since time.Time) ([]observation, error) {
+	opts := options.Find().SetProjection(bson.M{"card_id": 1, "variant_id": 1, "price": 1, "currency": 1, "condition": 1, "timestamp": 1})
+	cursor, err := e.db.Collection("prices").Find(ctx, rawSince("timestamp", since), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load prices: %v", err)
+	}
+	var prices []models.PricePoint
+	if err := cursor.All(ctx, &prices); err != nil {
+		return nil, fmt.Errorf("failed to decode prices: %v", err)
+	}
+
+	// Marketplaces quote in different currencies; compare like with like
+	if err := fx.ToBase(ctx, e.db, prices); err != nil {
+		return nil, fmt.Errorf("failed to convert prices: %v", err)
+	}
+
+	samples := make([]sample, 0, len(prices))
+	for _, p := range prices {
+		samples = append(samples, sample{cardID: p.CardID, variantID: p.VariantID, condition: p.Condition, price: p.Price})
+	}
+	return medians(samples), nil
+}
+
+// askMedians returns each card variant's median ask per condition for
+// listings created since the given time, in the base currency at now
+func (e *Estimator) askMedians(ctx context.Context, since, now time.Time) ([]observation, error) {
+	opts := options.Find().SetProjection(bson.M{"card_id": 1, "variant_id": 1, "price": 1, "currency": 1, "condition": 1, "created_at": 1})
+	cursor, err := e.db.Collection("listings").Find(ctx, rawSince("created_at", since), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load listings: %v", err)
+	}
+	var listings []models.Listing
+	if err := cursor.All(ctx, &listings); err != nil {
+		return nil, fmt.Errorf("failed to decode listings: %v", err)
+	}
+
+	if err := fx.ListingsToBase(ctx, e.db, listings, now); err != nil {
+		return nil, fmt.Errorf("failed to convert listings: %v", err)
+	}
+
+	samples := make([]sample, 0, len(listings))
+	for _, l := range listings {
+		samples = append(samples, sample{cardID: l.CardID, variantID: l.VariantID, condition: l.Condition, price: l.Price})
+	}
+	return medians(samples), nil
+}
+
+// rawSince filters raw copies recorded since the given time. Slabs have no
+// condition, so only raw copies count.
+func rawSince(timeField string, since time.Time) bson.M {
+	return bson.M{
+		timeField: bson.M{"$gte": since},
+		"outlier": bson.M{"$ne": true},
+		"grader":  bson.M{"$in": bson.A{nil, ""}},
+	}
+}
+
+// sample is one sale or ask in the base currency
+type sample struct {
+	cardID    primitive.ObjectID
+	variantID *primitive.ObjectID
+	condition string
+	price     float64
+}
+
+// medians returns each card variant's median price per condition
+func medians(samples []sample) []observation {
+	type key struct {
+		cardID    primitive.ObjectID
+		variantID primitive.ObjectID
+		condition string
+	}
+	prices := make(map[key][]float64)
+	for _, s := range samples {
+		if s.price <= 0 {
+			continue
+		}
+		k := key{cardID: s.cardID, condition: Of(s.condition)}
+		if s.variantID != nil {
+			k.variantID = *s.variantID
+		}
+		prices[k] = append(prices[k], s.price)
+	}
+
+	observations := make([]observation, 0, len(prices))
+	for k, values := range prices {
+		observations = append(observations, observation{CardID: k.cardID, VariantID: k.variantID, Condition: k.condition, Median: median(values)})
+	}
+	return observations
+}
+
+// cardGames maps every card to its game
+func (e *Estimator) cardGames(ctx context.Context) (map[primitive.ObjectID]string, error) {
+	cursor, err := e.db.Collection("cards").Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"game": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cards: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var cards []models.Card
+	if err := cursor.All(ctx, &cards); err != nil {
+		return nil, fmt.Errorf("failed to decode cards: %v", err)
+	}
+
+	games := make(map[primitive.ObjectID]string, len(cards))
+	for _, c := range cards {
+		games[c.ID] = c.Game
+	}
+	return games, nil
+}
+
+// Load returns a game's multipliers, filling any gaps with defaults
+func Load(ctx context.Context, db *mongo.Database, game string) (map[string]models.ConditionMultiplier, error) {
+	cursor, err := db.Collection("condition_multipliers").Find(ctx, bson.M{"game": game})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load condition multipliers: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stored []models.ConditionMultiplier
+	if err := cursor.All(ctx, &stored); err != nil {
+		return nil, fmt.Errorf("failed to decode condition multipliers: %v", err)
+	}
+
+	multipliers := make(map[string]models.ConditionMultiplier, len(All))
+	for _, condition := range All {
+		multipliers[condition] = models.ConditionMultiplier{
+			Game:       game,
+			Condition:  condition,
+			Multiplier: DefaultMultipliers[condition],
+			Basis:      "default",
+		}
+	}
+	for _, m := range stored {
+		if m.Multiplier > 0 {
+			multipliers[m.Condition] = m
+		}
+	}
+	return multipliers, nil
+}
+
+// median returns the median of values, which it sorts in place
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// round4 rounds a multiplier to four decimal places
+func round4(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -123,6 +123,9 @@ func createIndexes(db *mongo.Database) error {
 		{
 			Keys: bson.D{{Key: "name", Value: 1}},
 		},
+		{
+			Keys: bson.D{{Key: "liquidity.score", Value: -1}},
+		},
 	}
 
 	_, err = cardsCollection.Indexes().CreateMany(ctx, otherIndexes)
@@ -139,6 +142,17 @@ func createIndexes(db *mongo.Database) error {
 		{
 			Keys: bson.D{{Key: "card_id", Value: 1}, {Key: "source", Value: 1}, {Key: "timestamp", Value: -1}},
 		},
+		{
+			Keys: bson.D{{Key: "card_id", Value: 1}, {Key: "grader", Value: 1}, {Key: "grade", Value: 1}, {Key: "timestamp", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "card_id", Value: 1}, {Key: "variant_id", Value: 1}, {Key: "timestamp", Value: -1}},
+		},
+		{
+			// Ingested sales are deduplicated by marketplace sale ID
+			Keys:    bson.D{{Key: "source", Value: 1}, {Key: "external_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"external_id": bson.M{"$exists": true}}),
+		},
 		{
 			Keys: bson.D{{Key: "timestamp", Value: 1}},
 			Options: options.Index().SetExpireAfterSeconds(int32((5 * 365 * 24 * time.Hour).Seconds())), // 5 years TTL
@@ -158,11 +172,67 @@ func createIndexes(db *mongo.Database) error {
 		{
 			Keys: bson.D{{Key: "card_id", Value: 1}, {Key: "date", Value: -1}},
 		},
+		{
+			Keys: bson.D{{Key: "date", Value: -1}},
+		},
 	})
 	if err != nil {
 		fmt.Printf("Warning: Failed to create market data indexes: %v\n", err)
 	}
 
+	// Condition multipliers collection indexes
+	multipliersCollection := db.Collection("condition_multipliers")
+	_, err = multipliersCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "game", Value: 1}, {Key: "condition", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to create condition multiplier indexes: %v\n", err)
+	}
+
+	// FX rates collection indexes (one rate per currency per day)
+	fxRatesCollection := db.Collection("fx_rates")
+	_, err = fxRatesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "currency", Value: 1}, {Key: "date", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to create FX rate indexes: %v\n", err)
+	}
+
+	// Card views collection indexes (one document per card per day)
+	cardViewsCollection := db.Collection("card_views")
+	_, err = cardViewsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "card_id", Value: 1}, {Key: "date", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "date", Value: 1}},
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to create card view indexes: %v\n", err)
+	}
+
+	// Featured content collection indexes
+	featuredCollection := db.Collection("featured_content")
+	_, err = featuredCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "active", Value: 1}, {Key: "priority", Value: -1}},
+		},
+		{
+			Keys: bson.D{{Key: "type", Value: 1}, {Key: "card_id", Value: 1}, {Key: "window", Value: 1}},
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to create featured content indexes: %v\n", err)
+	}
+
 	// Saved charts collection indexes
 	chartsCollection := db.Collection("saved_charts")
 	_, err = chartsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
@@ -177,6 +247,32 @@ func createIndexes(db *mongo.Database) error {
 		fmt.Printf("Warning: Failed to create chart indexes: %v\n", err)
 	}
 
+	// Market index collections
+	indicesCollection := db.Collection("indices")
+	_, err = indicesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "game", Value: 1}, {Key: "category", Value: 1}},
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to create indices indexes: %v\n", err)
+	}
+
+	indexValuesCollection := db.Collection("index_values")
+	_, err = indexValuesCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "index_id", Value: 1}, {Key: "date", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to create index value indexes: %v\n", err)
+	}
+
 	// Listings collection indexes
 	listingsCollection := db.Collection("listings")
 	_, err = listingsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
@@ -186,11 +282,45 @@ func createIndexes(db *mongo.Database) error {
 		{
 			Keys: bson.D{{Key: "card_id", Value: 1}, {Key: "source", Value: 1}},
 		},
+		{
+			Keys: bson.D{{Key: "card_id", Value: 1}, {Key: "variant_id", Value: 1}},
+		},
+		{
+			// Ingested asks are upserted by marketplace listing ID
+			Keys:    bson.D{{Key: "source", Value: 1}, {Key: "external_id", Value: 1}},
+			Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"external_id": bson.M{"$exists": true}}),
+		},
 	})
 	if err != nil {
 		fmt.Printf("Warning: Failed to create listing indexes: %v\n", err)
 	}
 
+	// Marketplace ingestion collections
+	checkpointsCollection := db.Collection("ingest_checkpoints")
+	_, err = checkpointsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "source", Value: 1}, {Key: "kind", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to create ingest checkpoint indexes: %v\n", err)
+	}
+
+	productsCollection := db.Collection("external_products")
+	_, err = productsCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "source", Value: 1}, {Key: "external_id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "card_id", Value: 1}},
+		},
+	})
+	if err != nil {
+		fmt.Printf("Warning: Failed to create external product indexes: %v\n", err)
+	}
+
 	return nil
 }
 
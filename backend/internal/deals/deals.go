@@ -0,0 +1,263 @@
+package deals
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/conditions"
+	"github.com/jamesc159/monmetrics/internal/fx"
+	"github.com/jamesc159/monmetrics/internal/models"
+	"github.com/jamesc159/monmetrics/internal/variants"
+)
+
+// Deal types
+const (
+	FairValue = "fair_value" // Listing below the card's fair value in its condition
+	Arbitrage = "arbitrage"  // Listing below what reselling at the cheapest ask on another marketplace nets
+)
+
+// Types lists the accepted deal types
+var Types = []string{FairValue, Arbitrage}
+
+// DefaultMinConfidence is the fair value confidence below which listings are
+// not compared against it
+const DefaultMinConfidence = 0.3
+
+// Options narrow the catalog scanned for deals
+type Options struct {
+	Game          string
+	Condition     string // Canonical condition; empty for all
+	Fees          Schedule
+	MinConfidence float64
+}
+
+// Criteria select and rank deals for a response
+type Criteria struct {
+	Type        string  // Empty for both types
+	MinDiscount float64 // Percent
+	Limit       int
+}
+
+// Find returns every listing priced below its fair value or below the net
+// proceeds of reselling at the cheapest ask for the same printing, condition
+// and grade on another marketplace, after fees and shipping, as one deal per
+// listing and type. Amounts are in the base currency.
+func Find(ctx context.Context, db *mongo.Database, opts Options, now time.Time) ([]models.Deal, error) {
+	cardFilter := bson.M{}
+	if opts.Game != "" {
+		cardFilter["game"] = opts.Game
+	}
+	projection := bson.M{"name": 1, "set": 1, "game": 1, "image_url": 1, "fair_value": 1, "variants": 1}
+	cursor, err := db.Collection("cards").Find(ctx, cardFilter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cards: %v", err)
+	}
+	var cards []models.Card
+	if err := cursor.All(ctx, &cards); err != nil {
+		return nil, fmt.Errorf("failed to decode cards: %v", err)
+	}
+	if len(cards) == 0 {
+		return []models.Deal{}, nil
+	}
+
+	cardIDs := make([]primitive.ObjectID, len(cards))
+	for i, c := range cards {
+		cardIDs[i] = c.ID
+	}
+	cursor, err = db.Collection("listings").Find(ctx, bson.M{"card_id": bson.M{"$in": cardIDs}, "price": bson.M{"$gt": 0}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load listings: %v", err)
+	}
+	var listings []models.Listing
+	if err := cursor.All(ctx, &listings); err != nil {
+		return nil, fmt.Errorf("failed to decode listings: %v", err)
+	}
+	if err := fx.ListingsToBase(ctx, db, listings, now); err != nil {
+		return nil, err
+	}
+
+	byCard := make(map[primitive.ObjectID][]models.Listing)
+	for _, l := range listings {
+		byCard[l.CardID] = append(byCard[l.CardID], l)
+	}
+
+	minConfidence := opts.MinConfidence
+	if minConfidence <= 0 {
+		minConfidence = DefaultMinConfidence
+	}
+
+	multipliers := make(map[string]map[string]models.ConditionMultiplier)
+	found := make([]models.Deal, 0)
+	for _, card := range cards {
+		cardListings := byCard[card.ID]
+		if len(cardListings) == 0 {
+			continue
+		}
+		if _, ok := multipliers[card.Game]; !ok {
+			m, err := conditions.Load(ctx, db, card.Game)
+			if err != nil {
+				return nil, err
+			}
+			multipliers[card.Game] = m
+		}
+
+		for _, d := range ForCard(card, cardListings, multipliers[card.Game], opts.Fees, minConfidence) {
+			if opts.Condition == "" || conditions.Of(d.Listing.Condition) == opts.Condition {
+				found = append(found, d)
+			}
+		}
+	}
+	return found, nil
+}
+
+// ForCard returns the card's listings that cost less than the reference they
+// are compared against. Fair value deals cover raw copies of the default
+// printing when the card's fair value is at least minConfidence; arbitrage
+// deals compare each listing with the cheapest ask for the same printing,
+// condition and grade on every other marketplace.
+func ForCard(card models.Card, listings []models.Listing, multipliers map[string]models.ConditionMultiplier, fees Schedule, minConfidence float64) []models.Deal {
+	defaultVariant, hasDefault := variants.Default(card)
+	variantKey := func(l models.Listing) string {
+		if l.VariantID == nil || (hasDefault && *l.VariantID == defaultVariant.ID) {
+			return ""
+		}
+		return l.VariantID.Hex()
+	}
+
+	found := make([]models.Deal, 0)
+
+	if fv := card.FairValue; fv != nil && fv.Confidence >= minConfidence {
+		value := fv.SalesValue
+		if value <= 0 {
+			value = fv.Value
+		}
+		for _, l := range listings {
+			if l.Grader != "" || variantKey(l) != "" {
+				continue
+			}
+			multiplier, ok := multipliers[conditions.Of(l.Condition)]
+			if !ok || value <= 0 {
+				continue
+			}
+			reference := value * multiplier.Multiplier
+			if d, ok := newDeal(card, l, FairValue, fees.For(l.Source).Landed(l.Price), reference); ok {
+				d.Confidence = fv.Confidence
+				found = append(found, d)
+			}
+		}
+	}
+
+	// Cheapest ask for the same printing, condition and grade, per marketplace
+	type copyKey struct {
+		variant, condition, grader string
+		grade                      float64
+	}
+	cheapest := make(map[copyKey]map[string]float64)
+	keyOf := func(l models.Listing) copyKey {
+		return copyKey{variantKey(l), conditions.Of(l.Condition), l.Grader, l.Grade}
+	}
+	for _, l := range listings {
+		key := keyOf(l)
+		if cheapest[key] == nil {
+			cheapest[key] = make(map[string]float64)
+		}
+		if ask, ok := cheapest[key][l.Source]; !ok || l.Price < ask {
+			cheapest[key][l.Source] = l.Price
+		}
+	}
+
+	for _, l := range listings {
+		var (
+			reference float64
+			source    string
+		)
+		for other, ask := range cheapest[keyOf(l)] {
+			if other == l.Source {
+				continue
+			}
+			// Reselling at the cheapest ask there is the conservative exit
+			if net := fees.For(other).Net(ask); net > reference || (net == reference && other < source) {
+				reference, source = net, other
+			}
+		}
+		if source == "" {
+			continue
+		}
+		if d, ok := newDeal(card, l, Arbitrage, fees.For(l.Source).Landed(l.Price), reference); ok {
+			d.ReferenceSource = source
+			found = append(found, d)
+		}
+	}
+	return found
+}
+
+// Select filters deals by type and minimum discount, keeps each listing's
+// best deal, and returns them by discount, largest first
+func Select(found []models.Deal, criteria Criteria) []models.Deal {
+	best := make(map[primitive.ObjectID]models.Deal)
+	for _, d := range found {
+		if criteria.Type != "" && d.Type != criteria.Type {
+			continue
+		}
+		if d.Discount < criteria.MinDiscount {
+			continue
+		}
+		if current, ok := best[d.Listing.ID]; !ok || d.Discount > current.Discount {
+			best[d.Listing.ID] = d
+		}
+	}
+
+	selected := make([]models.Deal, 0, len(best))
+	for _, d := range best {
+		selected = append(selected, d)
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		if selected[i].Discount != selected[j].Discount {
+			return selected[i].Discount > selected[j].Discount
+		}
+		if selected[i].Savings != selected[j].Savings {
+			return selected[i].Savings > selected[j].Savings
+		}
+		return selected[i].Listing.ID.Hex() < selected[j].Listing.ID.Hex()
+	})
+
+	if criteria.Limit > 0 && len(selected) > criteria.Limit {
+		selected = selected[:criteria.Limit]
+	}
+	return selected
+}
+
+// newDeal builds a deal for a listing costing cost against reference,
+// reporting false when the listing saves nothing
+func newDeal(card models.Card, l models.Listing, dealType string, cost, reference float64) (models.Deal, bool) {
+	if reference <= 0 || cost >= reference {
+		return models.Deal{}, false
+	}
+	savings := reference - cost
+	return models.Deal{
+		Listing:   l,
+		CardID:    card.ID,
+		CardName:  card.Name,
+		Set:       card.Set,
+		Game:      card.Game,
+		ImageURL:  card.ImageURL,
+		Type:      dealType,
+		Cost:      round(cost),
+		Reference: round(reference),
+		Savings:   round(savings),
+		Discount:  math.Round(savings/reference*1000) / 10,
+	}, true
+}
+
+// round rounds v to cents
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
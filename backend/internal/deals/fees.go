@@ -0,0 +1,80 @@
+package deals
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Fees are a marketplace's estimated selling fees and per-order shipping
+type Fees struct {
+	Rate     float64 `json:"rate"`     // Share of the sale price, 0-1
+	Fixed    float64 `json:"fixed"`    // Per-order fee
+	Shipping float64 `json:"shipping"` // Per-order shipping, paid by the buyer on purchase and by the seller on resale
+}
+
+// DefaultFees are typical published seller fees and tracked shipping costs
+var DefaultFees = map[string]Fees{
+	"ebay":             {Rate: 0.1325, Fixed: 0.30, Shipping: 4.50},
+	"tcgplayer":        {Rate: 0.1075, Fixed: 0.30, Shipping: 1.50},
+	"tcgplayer_direct": {Rate: 0.1075, Fixed: 0.30, Shipping: 0},
+	"cardmarket":       {Rate: 0.05, Fixed: 0, Shipping: 2.00},
+}
+
+// fallbackFees apply to marketplaces without configured fees
+var fallbackFees = Fees{Rate: 0.13, Fixed: 0.30, Shipping: 4.50}
+
+// Schedule maps marketplaces to their fees
+type Schedule map[string]Fees
+
+// For returns a marketplace's fees, falling back to typical ones
+func (s Schedule) For(source string) Fees {
+	if f, ok := s[source]; ok {
+		return f
+	}
+	if f, ok := DefaultFees[source]; ok {
+		return f
+	}
+	return fallbackFees
+}
+
+// Landed is what a buyer pays for a listing at price, shipping included
+func (f Fees) Landed(price float64) float64 {
+	return price + f.Shipping
+}
+
+// Net is what a seller keeps from a sale at price after fees and shipping
+func (f Fees) Net(price float64) float64 {
+	return price*(1-f.Rate) - f.Fixed - f.Shipping
+}
+
+// ParseSchedule parses fee overrides such as "ebay:0.1325:0.30:4.50,cardmarket:0.05:0:2"
+// (source:rate:fixed:shipping). Marketplaces not listed keep their defaults.
+func ParseSchedule(raw string) (Schedule, error) {
+	schedule := Schedule{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		fields := strings.Split(part, ":")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid fees %q: expected source:rate:fixed:shipping", part)
+		}
+
+		values := make([]float64, 3)
+		for i, field := range fields[1:] {
+			v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil || v < 0 {
+				return nil, fmt.Errorf("invalid fees %q: rate, fixed and shipping must be non-negative numbers", part)
+			}
+			values[i] = v
+		}
+		if values[0] >= 1 {
+			return nil, fmt.Errorf("invalid fees %q: rate must be below 1", part)
+		}
+
+		schedule[fields[0]] = Fees{Rate: values[0], Fixed: values[1], Shipping: values[2]}
+	}
+	return schedule, nil
+}
@@ -0,0 +1,148 @@
+package downsample
+
+import (
+	"math"
+	"sort"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// MinPoints is the smallest threshold LTTB can honour (first, last and one bucket)
+const MinPoints = 3
+
+// LTTB selects up to threshold indices from the (xs, ys) series using the
+// Largest-Triangle-Three-Buckets algorithm. The first and last points are
+// always kept, and visually significant peaks and troughs survive.
+func LTTB(xs, ys []float64, threshold int) []int {
+	n := len(xs)
+	if threshold >= n || threshold < MinPoints {
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	indices := make([]int, 0, threshold)
+	indices = append(indices, 0)
+
+	bucketSize := float64(n-2) / float64(threshold-2)
+	a := 0
+	for i := 0; i < threshold-2; i++ {
+		// Average of the next bucket is the third triangle vertex
+		nextStart := int(math.Floor(float64(i+1)*bucketSize)) + 1
+		nextEnd := int(math.Floor(float64(i+2)*bucketSize)) + 1
+		if nextEnd > n {
+			nextEnd = n
+		}
+		avgX, avgY := 0.0, 0.0
+		for j := nextStart; j < nextEnd; j++ {
+			avgX += xs[j]
+			avgY += ys[j]
+		}
+		count := float64(nextEnd - nextStart)
+		avgX /= count
+		avgY /= count
+
+		// Pick the point in the current bucket forming the largest triangle
+		start := int(math.Floor(float64(i)*bucketSize)) + 1
+		end := int(math.Floor(float64(i+1)*bucketSize)) + 1
+		maxArea, selected := -1.0, start
+		for j := start; j < end; j++ {
+			area := math.Abs((xs[a]-avgX)*(ys[j]-ys[a]) - (xs[a]-xs[j])*(avgY-ys[a]))
+			if area > maxArea {
+				maxArea, selected = area, j
+			}
+		}
+
+		indices = append(indices, selected)
+		a = selected
+	}
+
+	return append(indices, n-1)
+}
+
+// Prices downsamples each source's series independently and merges the result
+// back in timestamp order, so ebay and tcgplayer keep their own shapes
+func Prices(prices []models.PricePoint, threshold int) []models.PricePoint {
+	bySource := make(map[string][]models.PricePoint)
+	var sources []string
+	for _, p := range prices {
+		if _, ok := bySource[p.Source]; !ok {
+			sources = append(sources, p.Source)
+		}
+		bySource[p.Source] = append(bySource[p.Source], p)
+	}
+
+	result := make([]models.PricePoint, 0, threshold*len(sources))
+	for _, source := range sources {
+		series := bySource[source]
+		xs := make([]float64, len(series))
+		ys := make([]float64, len(series))
+		for i, p := range series {
+			xs[i] = float64(p.Timestamp.Unix())
+			ys[i] = p.Price
+		}
+		for _, i := range LTTB(xs, ys, threshold) {
+			result = append(result, series[i])
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result
+}
+
+// Indicator downsamples a single indicator series with LTTB
+func Indicator(points []models.IndicatorPoint, threshold int) []models.IndicatorPoint {
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = float64(p.Timestamp.Unix())
+		ys[i] = p.Value
+	}
+
+	indices := LTTB(xs, ys, threshold)
+	result := make([]models.IndicatorPoint, len(indices))
+	for i, idx := range indices {
+		result[i] = points[idx]
+	}
+	return result
+}
+
+// Candles merges consecutive candles into at most threshold buckets, keeping
+// the first open, last close, overall high/low and total volume of each bucket
+func Candles(data []models.MarketData, threshold int) []models.MarketData {
+	if threshold <= 0 || len(data) <= threshold {
+		return data
+	}
+
+	result := make([]models.MarketData, 0, threshold)
+	bucketSize := float64(len(data)) / float64(threshold)
+	for i := 0; i < threshold; i++ {
+		start := int(math.Floor(float64(i) * bucketSize))
+		end := int(math.Floor(float64(i+1) * bucketSize))
+		if i == threshold-1 {
+			end = len(data)
+		}
+		if start >= end {
+			continue
+		}
+
+		merged := data[start]
+		weighted := merged.WeightedAvgPrice * float64(merged.Volume)
+		for _, d := range data[start+1 : end] {
+			merged.ClosePrice = d.ClosePrice
+			merged.HighPrice = math.Max(merged.HighPrice, d.HighPrice)
+			merged.LowPrice = math.Min(merged.LowPrice, d.LowPrice)
+			merged.Volume += d.Volume
+			weighted += d.WeightedAvgPrice * float64(d.Volume)
+		}
+		if merged.Volume > 0 {
+			merged.WeightedAvgPrice = math.Round(weighted/float64(merged.Volume)*100) / 100
+		}
+		result = append(result, merged)
+	}
+	return result
+}
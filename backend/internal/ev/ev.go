@@ -0,0 +1,180 @@
+package ev
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// DefaultDrivers is how many top-value singles are returned
+const DefaultDrivers = 10
+
+// ErrNoContents is returned for products without recorded contents
+var ErrNoContents = errors.New("no contents recorded for this product")
+
+// Validate checks a product's contents: at least one pack and slot, positive
+// slot counts, and pull rates in (0, 1] summing to at most 1 per slot
+func Validate(contents models.SealedContents) error {
+	if contents.PacksPerBox <= 0 {
+		return fmt.Errorf("packs_per_box must be positive")
+	}
+	if len(contents.Slots) == 0 {
+		return fmt.Errorf("at least one slot is required")
+	}
+	for i, slot := range contents.Slots {
+		if slot.Count <= 0 {
+			return fmt.Errorf("slot %d: count must be positive", i+1)
+		}
+		if len(slot.PullRates) == 0 {
+			return fmt.Errorf("slot %d: at least one pull rate is required", i+1)
+		}
+		total := 0.0
+		for _, pr := range slot.PullRates {
+			if strings.TrimSpace(pr.Rarity) == "" {
+				return fmt.Errorf("slot %d: rarity is required", i+1)
+			}
+			if pr.Rate <= 0 || pr.Rate > 1 {
+				return fmt.Errorf("slot %d: rate for %s must be between 0 and 1", i+1, pr.Rarity)
+			}
+			total += pr.Rate
+		}
+		if total > 1+1e-9 {
+			return fmt.Errorf("slot %d: pull rates sum to %.4g, more than 1", i+1, total)
+		}
+	}
+	return nil
+}
+
+// SinglePrice returns what a single is worth: its fair value when one has been
+// estimated, otherwise its current price
+func SinglePrice(card models.Card) float64 {
+	if card.FairValue != nil && card.FairValue.Value > 0 {
+		return card.FairValue.Value
+	}
+	return card.CurrentPrice
+}
+
+// Compute values a sealed product by opening it on paper: each slot card is
+// of a rarity with its pull rate, and every single of that rarity in the set
+// is equally likely. singles are the set's cards; those without a price are
+// ignored, and rarities with no priced singles count as zero. drivers limits
+// the top singles returned.
+func Compute(product models.Card, singles []models.Card, drivers int) (models.ExpectedValue, error) {
+	if product.Contents == nil {
+		return models.ExpectedValue{}, ErrNoContents
+	}
+	contents := *product.Contents
+	if drivers <= 0 {
+		drivers = DefaultDrivers
+	}
+
+	set := contents.Set
+	if set == "" {
+		set = product.Set
+	}
+
+	// Expected cards of each rarity per pack, keyed case-insensitively
+	perPack := make(map[string]float64)
+	labels := make(map[string]string)
+	order := make([]string, 0)
+	for _, slot := range contents.Slots {
+		for _, pr := range slot.PullRates {
+			key := rarityKey(pr.Rarity)
+			if _, seen := labels[key]; !seen {
+				labels[key] = strings.TrimSpace(pr.Rarity)
+				order = append(order, key)
+			}
+			perPack[key] += float64(slot.Count) * pr.Rate
+		}
+	}
+
+	// Priced singles per rarity
+	byRarity := make(map[string][]models.Card)
+	for _, card := range singles {
+		key := rarityKey(card.Rarity)
+		if _, pulled := perPack[key]; !pulled || SinglePrice(card) <= 0 {
+			continue
+		}
+		byRarity[key] = append(byRarity[key], card)
+	}
+
+	packs := float64(contents.PacksPerBox)
+	result := models.ExpectedValue{
+		CardID:       product.ID,
+		Set:          set,
+		ProductPrice: SinglePrice(product),
+		PacksPerBox:  contents.PacksPerBox,
+		Rarities:     make([]models.RarityValue, 0, len(order)),
+		Drivers:      make([]models.EVDriver, 0),
+	}
+
+	for _, key := range order {
+		cards := byRarity[key]
+		pulls := perPack[key] * packs
+		rv := models.RarityValue{
+			Rarity:      labels[key],
+			Cards:       len(cards),
+			PullsPerBox: round(pulls, 4),
+		}
+		if len(cards) == 0 {
+			result.UnpricedRarities = append(result.UnpricedRarities, labels[key])
+			result.Rarities = append(result.Rarities, rv)
+			continue
+		}
+
+		// Each card of the rarity is pulled pulls/len(cards) times on average
+		copies := pulls / float64(len(cards))
+		total := 0.0
+		for _, card := range cards {
+			price := SinglePrice(card)
+			total += price
+			result.Drivers = append(result.Drivers, models.EVDriver{
+				CardID:      card.ID,
+				Name:        card.Name,
+				Rarity:      card.Rarity,
+				Price:       price,
+				PullsPerBox: round(copies, 4),
+				Value:       price * copies,
+			})
+		}
+		rv.AvgPrice = round(total/float64(len(cards)), 2)
+		rv.Value = round(total*copies, 2)
+		result.ExpectedValue += total * copies
+		result.Rarities = append(result.Rarities, rv)
+	}
+
+	sort.Slice(result.Drivers, func(i, j int) bool { return result.Drivers[i].Value > result.Drivers[j].Value })
+	if len(result.Drivers) > drivers {
+		result.Drivers = result.Drivers[:drivers]
+	}
+	for i := range result.Drivers {
+		d := &result.Drivers[i]
+		if result.ExpectedValue > 0 {
+			d.Share = round(d.Value/result.ExpectedValue*100, 2)
+		}
+		d.Value = round(d.Value, 2)
+	}
+
+	result.PackValue = round(result.ExpectedValue/packs, 2)
+	result.ExpectedValue = round(result.ExpectedValue, 2)
+	if result.ProductPrice > 0 {
+		ratio := round(result.ExpectedValue/result.ProductPrice, 4)
+		result.Ratio = &ratio
+	}
+	return result, nil
+}
+
+// rarityKey normalizes a rarity for matching pull rates to singles
+func rarityKey(rarity string) string {
+	return strings.ToLower(strings.TrimSpace(rarity))
+}
+
+// round rounds v to the given number of decimal places
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
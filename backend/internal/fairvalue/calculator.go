@@ -0,0 +1,154 @@
+package fairvalue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/conditions"
+	"github.com/jamesc159/monmetrics/internal/fx"
+	"github.com/jamesc159/monmetrics/internal/models"
+	"github.com/jamesc159/monmetrics/internal/variants"
+)
+
+// Calculator computes and stores fair values on cards
+type Calculator struct {
+	db     *mongo.Database
+	params Params
+}
+
+// Result summarizes a fair value run
+type Result struct {
+	Cards     int `json:"cards"`
+	Estimated int `json:"estimated"` // Cards with recent sales to estimate from
+}
+
+// New creates a new Calculator instance
+func New(db *mongo.Database, params Params) *Calculator {
+	if params.WindowDays <= 0 {
+		params.WindowDays = DefaultWindowDays
+	}
+	if params.HalfLifeDays <= 0 {
+		params.HalfLifeDays = DefaultHalfLifeDays
+	}
+	return &Calculator{db: db, params: params}
+}
+
+// ForCard estimates a single card's fair value without storing it. ok is
+// false when the card has no recent sales.
+func (c *Calculator) ForCard(ctx context.Context, card models.Card) (fv models.FairValue, ok bool, err error) {
+	multipliers, err := conditions.Load(ctx, c.db, card.Game)
+	if err != nil {
+		return models.FairValue{}, false, err
+	}
+	return c.estimate(ctx, card, multipliers, time.Now().UTC())
+}
+
+// Run recomputes every card's fair value and writes it to cards.fair_value
+// so search can sort on it. Cards without recent sales lose any stale value.
+func (c *Calculator) Run(ctx context.Context) (Result, error) {
+	var result Result
+	now := time.Now().UTC()
+
+	cursor, err := c.db.Collection("cards").Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"game": 1, "variants": 1}))
+	if err != nil {
+		return result, fmt.Errorf("failed to list cards: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var cards []models.Card
+	if err := cursor.All(ctx, &cards); err != nil {
+		return result, fmt.Errorf("failed to decode cards: %v", err)
+	}
+
+	byGame := make(map[string]map[string]models.ConditionMultiplier)
+	writes := make([]mongo.WriteModel, 0, len(cards))
+	for _, card := range cards {
+		multipliers, ok := byGame[card.Game]
+		if !ok {
+			if multipliers, err = conditions.Load(ctx, c.db, card.Game); err != nil {
+				return result, err
+			}
+			byGame[card.Game] = multipliers
+		}
+
+		fv, ok, err := c.estimate(ctx, card, multipliers, now)
+		if err != nil {
+			return result, err
+		}
+
+		update := bson.M{"$unset": bson.M{"fair_value": ""}}
+		if ok {
+			update = bson.M{"$set": bson.M{"fair_value": fv}}
+			result.Estimated++
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": card.ID}).
+			SetUpdate(update))
+	}
+
+	if len(writes) > 0 {
+		if _, err := c.db.Collection("cards").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return result, fmt.Errorf("failed to write fair values: %v", err)
+		}
+	}
+	result.Cards = len(writes)
+
+	return result, nil
+}
+
+// estimate loads the default variant's raw, non-outlier sales in the window
+// and raw listings, normalized to USD, and runs Estimate over them
+func (c *Calculator) estimate(ctx context.Context, card models.Card, multipliers map[string]models.ConditionMultiplier, now time.Time) (models.FairValue, bool, error) {
+	variantMatch := variants.MatchDefault(card)
+	raw := bson.M{"$in": bson.A{nil, ""}}
+
+	salesFilter := bson.M{
+		"card_id":    card.ID,
+		"variant_id": variantMatch,
+		"grader":     raw,
+		"outlier":    bson.M{"$ne": true},
+		"timestamp":  bson.M{"$gte": now.AddDate(0, 0, -c.params.WindowDays), "$lte": now},
+	}
+	cursor, err := c.db.Collection("prices").Find(ctx, salesFilter,
+		options.Find().SetProjection(bson.M{"price": 1, "currency": 1, "volume": 1, "condition": 1, "timestamp": 1}))
+	if err != nil {
+		return models.FairValue{}, false, fmt.Errorf("failed to load prices for card %s: %v", card.ID.Hex(), err)
+	}
+	var sales []models.PricePoint
+	err = cursor.All(ctx, &sales)
+	cursor.Close(ctx)
+	if err != nil {
+		return models.FairValue{}, false, fmt.Errorf("failed to decode prices for card %s: %v", card.ID.Hex(), err)
+	}
+	if len(sales) == 0 {
+		return models.FairValue{}, false, nil
+	}
+
+	listingsFilter := bson.M{"card_id": card.ID, "variant_id": variantMatch, "grader": raw}
+	cursor, err = c.db.Collection("listings").Find(ctx, listingsFilter,
+		options.Find().SetProjection(bson.M{"price": 1, "currency": 1, "condition": 1}))
+	if err != nil {
+		return models.FairValue{}, false, fmt.Errorf("failed to load listings for card %s: %v", card.ID.Hex(), err)
+	}
+	var listings []models.Listing
+	err = cursor.All(ctx, &listings)
+	cursor.Close(ctx)
+	if err != nil {
+		return models.FairValue{}, false, fmt.Errorf("failed to decode listings for card %s: %v", card.ID.Hex(), err)
+	}
+
+	if err := fx.ToBase(ctx, c.db, sales); err != nil {
+		return models.FairValue{}, false, fmt.Errorf("failed to convert prices for card %s: %v", card.ID.Hex(), err)
+	}
+	if err := fx.ListingsToBase(ctx, c.db, listings, now); err != nil {
+		return models.FairValue{}, false, fmt.Errorf("failed to convert listings for card %s: %v", card.ID.Hex(), err)
+	}
+
+	fv, ok := Estimate(sales, listings, multipliers, c.params, now)
+	return fv, ok, nil
+}
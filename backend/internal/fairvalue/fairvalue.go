@@ -0,0 +1,211 @@
+package fairvalue
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/conditions"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+const (
+	// DefaultWindowDays is the sales lookback for the estimate
+	DefaultWindowDays = 90
+	// DefaultHalfLifeDays is the sale age at which its weight halves
+	DefaultHalfLifeDays = 14
+
+	// Range percentiles of the weighted sale distribution
+	lowQuantile  = 0.2
+	highQuantile = 0.8
+
+	// Confidence weights; they sum to 1 so confidence spans 0-1
+	sampleWeight     = 0.5
+	dispersionWeight = 0.3
+	recencyWeight    = 0.2
+
+	// halfConfidenceSales is the effective sample size at which the sample
+	// component reaches one half
+	halfConfidenceSales = 5
+)
+
+// Params tunes the estimator
+type Params struct {
+	WindowDays   int
+	HalfLifeDays float64
+}
+
+// weighted is a sale's Near Mint-equivalent price and its weight
+type weighted struct {
+	price  float64
+	weight float64
+}
+
+// Estimate derives a card's fair value from its recent raw sales and active
+// raw listings. Each sale is converted to Near Mint terms with the game's
+// condition multipliers and weighted by recency (exponential decay with the
+// configured half-life) and volume. The point estimate is the weighted median
+// and the range spans the weighted 20th-80th percentiles. The cheapest ask in
+// any condition, in Near Mint terms, caps the estimate since a buyer could
+// take that copy instead. ok is false when there are no sales in the window.
+func Estimate(sales []models.PricePoint, listings []models.Listing, multipliers map[string]models.ConditionMultiplier, params Params, now time.Time) (models.FairValue, bool) {
+	if params.WindowDays <= 0 {
+		params.WindowDays = DefaultWindowDays
+	}
+	if params.HalfLifeDays <= 0 {
+		params.HalfLifeDays = DefaultHalfLifeDays
+	}
+
+	since := now.AddDate(0, 0, -params.WindowDays)
+	points := make([]weighted, 0, len(sales))
+	var newest time.Time
+	for _, s := range sales {
+		if s.Timestamp.Before(since) || s.Timestamp.After(now) || s.Price <= 0 {
+			continue
+		}
+		multiplier := multipliers[conditions.Of(s.Condition)].Multiplier
+		if multiplier <= 0 {
+			continue
+		}
+
+		ageDays := now.Sub(s.Timestamp).Hours() / 24
+		volume := math.Max(1, float64(s.Volume))
+		points = append(points, weighted{
+			price:  s.Price / multiplier,
+			weight: math.Exp2(-ageDays/params.HalfLifeDays) * volume,
+		})
+		if s.Timestamp.After(newest) {
+			newest = s.Timestamp
+		}
+	}
+	if len(points) == 0 {
+		return models.FairValue{}, false
+	}
+
+	sort.Slice(points, func(i, j int) bool { return points[i].price < points[j].price })
+	result := models.FairValue{
+		Value:      quantile(points, 0.5),
+		SalesValue: round(quantile(points, 0.5), 2),
+		Low:        quantile(points, lowQuantile),
+		High:       quantile(points, highQuantile),
+		Sales:      len(points),
+		WindowDays: params.WindowDays,
+		ComputedAt: now,
+	}
+
+	// Confidence reflects the sales themselves, before any listing cap
+	result.Confidence = confidence(points, result, newest, params.HalfLifeDays, now)
+
+	// Cap at the cheapest ask, in Near Mint terms, across conditions
+	asks := lowestAsks(listings)
+	for condition, ask := range asks {
+		multiplier := multipliers[condition].Multiplier
+		if multiplier <= 0 {
+			continue
+		}
+		ceiling := ask / multiplier
+		if result.Ceiling == nil || ceiling < *result.Ceiling {
+			c := round(ceiling, 2)
+			result.Ceiling = &c
+		}
+	}
+	if result.Ceiling != nil && result.Value > *result.Ceiling {
+		result.Value = *result.Ceiling
+		result.Capped = true
+	}
+	// Keep the range around the (possibly capped) estimate
+	if result.Ceiling != nil {
+		result.High = math.Min(result.High, *result.Ceiling)
+	}
+	result.High = math.Max(result.High, result.Value)
+	result.Low = math.Min(result.Low, result.Value)
+
+	// Per-condition values, each capped by its own cheapest ask
+	result.Conditions = make([]models.FairValueCondition, 0, len(conditions.All))
+	for _, condition := range conditions.All {
+		entry := models.FairValueCondition{
+			Condition: condition,
+			Value:     round(result.Value*multipliers[condition].Multiplier, 2),
+		}
+		if ask, ok := asks[condition]; ok {
+			a := ask
+			entry.LowestAsk = &a
+			if ask < entry.Value {
+				entry.Value = ask
+				entry.Capped = true
+			}
+		}
+		result.Conditions = append(result.Conditions, entry)
+	}
+
+	result.Value = round(result.Value, 2)
+	result.Low = round(result.Low, 2)
+	result.High = round(result.High, 2)
+	return result, true
+}
+
+// lowestAsks returns the cheapest listing price per condition
+func lowestAsks(listings []models.Listing) map[string]float64 {
+	asks := make(map[string]float64)
+	for _, l := range listings {
+		if l.Price <= 0 {
+			continue
+		}
+		condition := conditions.Of(l.Condition)
+		if ask, ok := asks[condition]; !ok || l.Price < ask {
+			asks[condition] = l.Price
+		}
+	}
+	return asks
+}
+
+// confidence scores the estimate from the effective sample size, how tight
+// the range is relative to the value, and how recent the latest sale is
+func confidence(points []weighted, fv models.FairValue, newest time.Time, halfLifeDays float64, now time.Time) float64 {
+	var sum, sumSquares float64
+	for _, p := range points {
+		sum += p.weight
+		sumSquares += p.weight * p.weight
+	}
+	effective := 0.0
+	if sumSquares > 0 {
+		effective = sum * sum / sumSquares
+	}
+	sample := effective / (effective + halfConfidenceSales)
+
+	dispersion := 0.0
+	if fv.Value > 0 {
+		dispersion = 1 - math.Min(1, (fv.High-fv.Low)/fv.Value)
+	}
+
+	recency := math.Exp2(-now.Sub(newest).Hours() / 24 / halfLifeDays)
+
+	return round(sampleWeight*sample+dispersionWeight*dispersion+recencyWeight*recency, 2)
+}
+
+// quantile returns the weighted q-quantile of points sorted by price
+func quantile(points []weighted, q float64) float64 {
+	var total float64
+	for _, p := range points {
+		total += p.weight
+	}
+	if total <= 0 {
+		return points[len(points)/2].price
+	}
+
+	target := q * total
+	var cumulative float64
+	for _, p := range points {
+		cumulative += p.weight
+		if cumulative >= target {
+			return p.price
+		}
+	}
+	return points[len(points)-1].price
+}
+
+// round rounds v to the given number of decimal places
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -0,0 +1,145 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/analytics"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// DefaultModel is the model used when none is requested (and for free users)
+const DefaultModel = "holt"
+
+// Confidence is the coverage of the returned forecast bands
+const Confidence = 0.95
+
+// z95 is the two-sided normal quantile for 95% bands
+const z95 = 1.96
+
+// Models returns the available model names in sorted order
+func Models() []string {
+	names := make([]string, 0, len(fitters))
+	for name := range fitters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Forecast fits the named model on daily closes, oldest first, and projects
+// horizon days ahead. Days without a close carry the previous one forward so
+// the models see one value per calendar day. The trailing part of the series
+// is also held out and re-forecast to report how accurate the model has
+// recently been.
+func Forecast(model string, closes []analytics.Point, horizon int) (models.Forecast, error) {
+	fitFn, ok := fitters[model]
+	if !ok {
+		return models.Forecast{}, fmt.Errorf("unknown model %q", model)
+	}
+
+	values := calendarDays(closes)
+	if len(values) < minObservations[model] {
+		return models.Forecast{}, fmt.Errorf("model %s needs at least %d days of price history", model, minObservations[model])
+	}
+
+	fitted, err := fitFn(values, horizon)
+	if err != nil {
+		return models.Forecast{}, err
+	}
+
+	last := day(closes[len(closes)-1].Date)
+	result := models.Forecast{
+		Model:        model,
+		HorizonDays:  horizon,
+		Confidence:   Confidence,
+		Observations: len(values),
+		Parameters:   fitted.params,
+		Points:       make([]models.ForecastPoint, horizon),
+	}
+	for i := range fitted.point {
+		value := fitted.point[i]
+		band := z95 * fitted.stdErr[i]
+		result.Points[i] = models.ForecastPoint{
+			Date:  last.AddDate(0, 0, i+1),
+			Value: roundCents(math.Max(value, 0)),
+			Lower: roundCents(math.Max(value-band, 0)),
+			Upper: roundCents(math.Max(value+band, 0)),
+		}
+	}
+
+	result.Backtest = holdoutAccuracy(fitFn, values, horizon, minObservations[model])
+	return result, nil
+}
+
+// calendarDays reindexes closes onto consecutive calendar days from the first
+// close to the last, forward-filling days without one
+func calendarDays(closes []analytics.Point) []float64 {
+	if len(closes) == 0 {
+		return nil
+	}
+
+	first := day(closes[0].Date)
+	days := int(day(closes[len(closes)-1].Date).Sub(first).Hours()/24) + 1
+	values := make([]float64, 0, days)
+	for _, p := range closes {
+		offset := int(day(p.Date).Sub(first).Hours() / 24)
+		if offset < len(values) {
+			// Several closes on one day keep the latest
+			values[len(values)-1] = p.Value
+			continue
+		}
+		for len(values) < offset {
+			values = append(values, values[len(values)-1])
+		}
+		values = append(values, p.Value)
+	}
+	return values
+}
+
+// day truncates a time to midnight UTC
+func day(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// holdoutAccuracy refits on all but the last k points and scores the forecast
+// of those k points, where k is the horizon capped at a fifth of the series
+func holdoutAccuracy(fitFn fitter, values []float64, horizon, minTrain int) *models.ForecastAccuracy {
+	holdout := horizon
+	if cap := len(values) / 5; holdout > cap {
+		holdout = cap
+	}
+	train := len(values) - holdout
+	if holdout < 1 || train < minTrain {
+		return nil
+	}
+
+	fitted, err := fitFn(values[:train], holdout)
+	if err != nil {
+		return nil
+	}
+
+	var absPctSum, sqSum float64
+	for i, predicted := range fitted.point {
+		actual := values[train+i]
+		diff := actual - predicted
+		sqSum += diff * diff
+		if actual != 0 {
+			absPctSum += math.Abs(diff / actual)
+		}
+	}
+
+	return &models.ForecastAccuracy{
+		HoldoutDays: holdout,
+		MAPE:        math.Round(absPctSum/float64(holdout)*100*100) / 100,
+		RMSE:        roundCents(math.Sqrt(sqSum / float64(holdout))),
+	}
+}
+
+// roundCents rounds a price to two decimal places
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,206 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+)
+
+// seasonLength is the weekly cycle used by Holt-Winters on daily data
+const seasonLength = 7
+
+// fit is a fitted model's projection with per-step standard errors
+type fit struct {
+	point  []float64
+	stdErr []float64
+	params map[string]float64
+}
+
+// fitter fits a model on values and projects it horizon steps ahead
+type fitter func(values []float64, horizon int) (fit, error)
+
+// fitters maps model names to their implementations
+var fitters = map[string]fitter{
+	"ses":          fitSES,
+	"holt":         fitHolt,
+	"holt_winters": fitHoltWinters,
+	"linear":       fitLinear,
+}
+
+// minObservations is the shortest series each model can be fitted on
+var minObservations = map[string]int{
+	"ses":          3,
+	"holt":         4,
+	"holt_winters": 2 * seasonLength,
+	"linear":       3,
+}
+
+// smoothingGrid is the set of candidate smoothing parameters searched per model
+var smoothingGrid = []float64{0.05, 0.1, 0.15, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.9, 0.95}
+
+// fitSES fits simple exponential smoothing (flat forecast at the last level)
+func fitSES(values []float64, horizon int) (fit, error) {
+	bestSSE, bestAlpha, bestLevel := math.Inf(1), 0.0, 0.0
+	for _, alpha := range smoothingGrid {
+		level, sse := values[0], 0.0
+		for _, y := range values[1:] {
+			err := y - level
+			sse += err * err
+			level = alpha*y + (1-alpha)*level
+		}
+		if sse < bestSSE {
+			bestSSE, bestAlpha, bestLevel = sse, alpha, level
+		}
+	}
+
+	sigma := math.Sqrt(bestSSE / float64(len(values)-1))
+	result := fit{params: map[string]float64{"alpha": bestAlpha}}
+	for h := 1; h <= horizon; h++ {
+		result.point = append(result.point, bestLevel)
+		result.stdErr = append(result.stdErr, sigma*math.Sqrt(1+float64(h-1)*bestAlpha*bestAlpha))
+	}
+	return result, nil
+}
+
+// fitHolt fits Holt's linear trend method
+func fitHolt(values []float64, horizon int) (fit, error) {
+	bestSSE := math.Inf(1)
+	var bestAlpha, bestBeta, bestLevel, bestTrend float64
+	for _, alpha := range smoothingGrid {
+		for _, beta := range smoothingGrid {
+			level, trend, sse := values[0], values[1]-values[0], 0.0
+			for _, y := range values[1:] {
+				err := y - (level + trend)
+				sse += err * err
+				next := alpha*y + (1-alpha)*(level+trend)
+				trend = beta*(next-level) + (1-beta)*trend
+				level = next
+			}
+			if sse < bestSSE {
+				bestSSE, bestAlpha, bestBeta, bestLevel, bestTrend = sse, alpha, beta, level, trend
+			}
+		}
+	}
+
+	sigma := math.Sqrt(bestSSE / float64(len(values)-2))
+	return fit{
+		point:  trendProjection(bestLevel, bestTrend, nil, horizon),
+		stdErr: holtStdErr(sigma, bestAlpha, bestBeta, horizon),
+		params: map[string]float64{"alpha": bestAlpha, "beta": bestBeta},
+	}, nil
+}
+
+// fitHoltWinters fits additive Holt-Winters with a weekly season
+func fitHoltWinters(values []float64, horizon int) (fit, error) {
+	m := seasonLength
+	if len(values) < 2*m {
+		return fit{}, fmt.Errorf("holt_winters needs at least %d observations", 2*m)
+	}
+
+	first, second := mean(values[:m]), mean(values[m:2*m])
+	grid := []float64{0.1, 0.3, 0.5, 0.7, 0.9}
+
+	bestSSE := math.Inf(1)
+	var bestAlpha, bestBeta, bestGamma, bestLevel, bestTrend float64
+	var bestSeason []float64
+	for _, alpha := range grid {
+		for _, beta := range grid {
+			for _, gamma := range grid {
+				level, trend := first, (second-first)/float64(m)
+				season := make([]float64, len(values))
+				for i := 0; i < m; i++ {
+					season[i] = values[i] - first
+				}
+
+				sse := 0.0
+				for t := m; t < len(values); t++ {
+					y := values[t]
+					err := y - (level + trend + season[t-m])
+					sse += err * err
+					next := alpha*(y-season[t-m]) + (1-alpha)*(level+trend)
+					trend = beta*(next-level) + (1-beta)*trend
+					season[t] = gamma*(y-next) + (1-gamma)*season[t-m]
+					level = next
+				}
+
+				if sse < bestSSE {
+					bestSSE, bestAlpha, bestBeta, bestGamma = sse, alpha, beta, gamma
+					bestLevel, bestTrend, bestSeason = level, trend, season[len(values)-m:]
+				}
+			}
+		}
+	}
+
+	sigma := math.Sqrt(bestSSE / float64(len(values)-m))
+	return fit{
+		point:  trendProjection(bestLevel, bestTrend, bestSeason, horizon),
+		stdErr: holtStdErr(sigma, bestAlpha, bestBeta, horizon),
+		params: map[string]float64{"alpha": bestAlpha, "beta": bestBeta, "gamma": bestGamma},
+	}, nil
+}
+
+// fitLinear fits an ordinary least squares trend line over time
+func fitLinear(values []float64, horizon int) (fit, error) {
+	n := float64(len(values))
+	meanX := (n - 1) / 2
+	meanY := mean(values)
+
+	var sxx, sxy float64
+	for i, y := range values {
+		dx := float64(i) - meanX
+		sxx += dx * dx
+		sxy += dx * (y - meanY)
+	}
+	slope := sxy / sxx
+	intercept := meanY - slope*meanX
+
+	sse := 0.0
+	for i, y := range values {
+		err := y - (intercept + slope*float64(i))
+		sse += err * err
+	}
+	sigma := math.Sqrt(sse / (n - 2))
+
+	result := fit{params: map[string]float64{"intercept": intercept, "slope": slope}}
+	for h := 1; h <= horizon; h++ {
+		x := n - 1 + float64(h)
+		result.point = append(result.point, intercept+slope*x)
+		result.stdErr = append(result.stdErr, sigma*math.Sqrt(1+1/n+(x-meanX)*(x-meanX)/sxx))
+	}
+	return result, nil
+}
+
+// trendProjection projects level + h*trend, plus the matching seasonal term if any
+func trendProjection(level, trend float64, season []float64, horizon int) []float64 {
+	points := make([]float64, horizon)
+	for h := 1; h <= horizon; h++ {
+		points[h-1] = level + float64(h)*trend
+		if len(season) > 0 {
+			points[h-1] += season[(h-1)%len(season)]
+		}
+	}
+	return points
+}
+
+// holtStdErr returns h-step standard errors for Holt's method:
+// sigma * sqrt(1 + sum_{j=1}^{h-1} alpha^2 (1 + j*beta)^2)
+func holtStdErr(sigma, alpha, beta float64, horizon int) []float64 {
+	errs := make([]float64, horizon)
+	sum := 1.0
+	for h := 1; h <= horizon; h++ {
+		if h > 1 {
+			j := float64(h - 1)
+			sum += alpha * alpha * (1 + j*beta) * (1 + j*beta)
+		}
+		errs[h-1] = sigma * math.Sqrt(sum)
+	}
+	return errs
+}
+
+// mean returns the arithmetic mean of values
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
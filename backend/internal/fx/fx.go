@@ -0,0 +1,237 @@
+package fx
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// Base is the currency prices are normalized to and rates are quoted against
+const Base = "USD"
+
+// Currencies lists the supported currencies
+var Currencies = []string{"USD", "EUR", "GBP", "JPY"}
+
+// sourceCurrencies maps marketplaces that don't quote in the base currency
+var sourceCurrencies = map[string]string{
+	"cardmarket": "EUR",
+}
+
+// ErrNoRate is returned when the table has no rate for a currency
+var ErrNoRate = errors.New("no FX rate")
+
+// Normalize returns the canonical code for a currency, e.g. "eur" -> "EUR"
+func Normalize(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for _, c := range Currencies {
+		if c == code {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// Of returns a stored currency, treating empty as the base currency
+func Of(currency string) string {
+	if currency == "" {
+		return Base
+	}
+	return currency
+}
+
+// ForSource returns the currency a marketplace quotes prices in
+func ForSource(source string) string {
+	if c, ok := sourceCurrencies[source]; ok {
+		return c
+	}
+	return Base
+}
+
+// Table holds dated rates per currency, oldest first
+type Table struct {
+	rates map[string][]models.FXRate
+}
+
+// NewTable indexes rates by currency
+func NewTable(rates []models.FXRate) *Table {
+	t := &Table{rates: make(map[string][]models.FXRate)}
+	for _, r := range rates {
+		t.rates[r.Currency] = append(t.rates[r.Currency], r)
+	}
+	for _, series := range t.rates {
+		sort.Slice(series, func(i, j int) bool { return series[i].Date.Before(series[j].Date) })
+	}
+	return t
+}
+
+// Rate returns units of currency per base unit as of the given time: the
+// latest rate on or before it, or the earliest known rate for times before
+// the table starts
+func (t *Table) Rate(currency string, at time.Time) (float64, error) {
+	if currency == Base {
+		return 1, nil
+	}
+
+	series := t.rates[currency]
+	if len(series) == 0 {
+		return 0, fmt.Errorf("%w for %s", ErrNoRate, currency)
+	}
+
+	i := sort.Search(len(series), func(i int) bool { return series[i].Date.After(at) })
+	if i == 0 {
+		return series[0].Rate, nil
+	}
+	return series[i-1].Rate, nil
+}
+
+// Convert converts an amount between currencies at the rates in effect at the given time
+func (t *Table) Convert(amount float64, from, to string, at time.Time) (float64, error) {
+	from, to = Of(from), Of(to)
+	if from == to {
+		return amount, nil
+	}
+
+	fromRate, err := t.Rate(from, at)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := t.Rate(to, at)
+	if err != nil {
+		return 0, err
+	}
+	return roundCents(amount / fromRate * toRate), nil
+}
+
+// ConvertPrices converts each price point in place as of its own timestamp
+func (t *Table) ConvertPrices(prices []models.PricePoint, to string) error {
+	for i := range prices {
+		p := &prices[i]
+		price, err := t.Convert(p.Price, p.Currency, to, p.Timestamp)
+		if err != nil {
+			return err
+		}
+		p.Price = price
+		p.Currency = to
+	}
+	return nil
+}
+
+// ConvertListings converts listings in place at the rates in effect at the given time
+func (t *Table) ConvertListings(listings []models.Listing, to string, at time.Time) error {
+	for i := range listings {
+		l := &listings[i]
+		price, err := t.Convert(l.Price, l.Currency, to, at)
+		if err != nil {
+			return err
+		}
+		l.Price = price
+		l.Currency = to
+	}
+	return nil
+}
+
+// ConvertCandles converts base-currency candles in place as of each candle's date
+func (t *Table) ConvertCandles(candles []models.MarketData, to string) error {
+	for i := range candles {
+		c := &candles[i]
+		for _, v := range []*float64{&c.OpenPrice, &c.HighPrice, &c.LowPrice, &c.ClosePrice, &c.WeightedAvgPrice} {
+			converted, err := t.Convert(*v, Base, to, c.Date)
+			if err != nil {
+				return err
+			}
+			*v = converted
+		}
+	}
+	return nil
+}
+
+// ConvertCard converts a card's stored base-currency price summary in place:
+// the all-time high and low as of their dates, the current price as of now
+func (t *Table) ConvertCard(card *models.Card, to string, now time.Time) error {
+	var err error
+	if card.CurrentPrice, err = t.Convert(card.CurrentPrice, Base, to, now); err != nil {
+		return err
+	}
+	if card.AllTimeHigh, err = t.Convert(card.AllTimeHigh, Base, to, card.ATHDate); err != nil {
+		return err
+	}
+	if card.AllTimeLow, err = t.Convert(card.AllTimeLow, Base, to, card.ATLDate); err != nil {
+		return err
+	}
+	if card.FairValue != nil {
+		if err := t.ConvertFairValue(card.FairValue, to, now); err != nil {
+			return err
+		}
+	}
+	card.Currency = to
+	return nil
+}
+
+// ConvertFairValue converts a base-currency fair value in place at the rates
+// in effect at the given time
+func (t *Table) ConvertFairValue(fv *models.FairValue, to string, at time.Time) error {
+	amounts := []*float64{&fv.Value, &fv.SalesValue, &fv.Low, &fv.High, fv.Ceiling}
+	for i := range fv.Conditions {
+		amounts = append(amounts, &fv.Conditions[i].Value, fv.Conditions[i].LowestAsk)
+	}
+	for _, v := range amounts {
+		if v == nil {
+			continue
+		}
+		converted, err := t.Convert(*v, Base, to, at)
+		if err != nil {
+			return err
+		}
+		*v = converted
+	}
+	fv.Currency = to
+	return nil
+}
+
+// ConvertExpectedValue converts a base-currency expected value in place at
+// the rates in effect at the given time
+func (t *Table) ConvertExpectedValue(ev *models.ExpectedValue, to string, at time.Time) error {
+	amounts := []*float64{&ev.ProductPrice, &ev.PackValue, &ev.ExpectedValue}
+	for i := range ev.Rarities {
+		amounts = append(amounts, &ev.Rarities[i].AvgPrice, &ev.Rarities[i].Value)
+	}
+	for i := range ev.Drivers {
+		amounts = append(amounts, &ev.Drivers[i].Price, &ev.Drivers[i].Value)
+	}
+	for _, v := range amounts {
+		converted, err := t.Convert(*v, Base, to, at)
+		if err != nil {
+			return err
+		}
+		*v = converted
+	}
+	ev.Currency = to
+	return nil
+}
+
+// ConvertDeals converts base-currency deals and their listings in place at
+// the rates in effect at the given time
+func (t *Table) ConvertDeals(deals []models.Deal, to string, at time.Time) error {
+	for i := range deals {
+		d := &deals[i]
+		for _, v := range []*float64{&d.Listing.Price, &d.Cost, &d.Reference, &d.Savings} {
+			converted, err := t.Convert(*v, Base, to, at)
+			if err != nil {
+				return err
+			}
+			*v = converted
+		}
+		d.Listing.Currency = to
+	}
+	return nil
+}
+
+// roundCents rounds an amount to two decimal places
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,182 @@
+package fx
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// Load reads every stored rate into a table
+func Load(ctx context.Context, db *mongo.Database) (*Table, error) {
+	cursor, err := db.Collection("fx_rates").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load FX rates: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var rates []models.FXRate
+	if err := cursor.All(ctx, &rates); err != nil {
+		return nil, fmt.Errorf("failed to decode FX rates: %v", err)
+	}
+	return NewTable(rates), nil
+}
+
+// Save upserts rates keyed by (currency, date) and returns how many were
+// inserted or changed
+func Save(ctx context.Context, db *mongo.Database, rates []models.FXRate) (int64, error) {
+	if len(rates) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now().UTC()
+	writes := make([]mongo.WriteModel, 0, len(rates))
+	for _, r := range rates {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"currency": r.Currency, "date": r.Date}).
+			SetUpdate(bson.M{"$set": bson.M{"rate": r.Rate, "source": r.Source, "updated_at": now}}).
+			SetUpsert(true))
+	}
+
+	result, err := db.Collection("fx_rates").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, fmt.Errorf("failed to save FX rates: %v", err)
+	}
+	return result.UpsertedCount + result.ModifiedCount, nil
+}
+
+// RateInput is one rate as written in a rates file or admin request
+type RateInput struct {
+	Date     string  `json:"date"`     // YYYY-MM-DD
+	Currency string  `json:"currency"` // ISO 4217, e.g. "EUR"
+	Rate     float64 `json:"rate"`     // Units of currency per USD
+}
+
+// Parse validates rate inputs, truncating dates to the day
+func Parse(inputs []RateInput, source string) ([]models.FXRate, error) {
+	rates := make([]models.FXRate, 0, len(inputs))
+	for i, in := range inputs {
+		currency, ok := Normalize(in.Currency)
+		if !ok {
+			return nil, fmt.Errorf("rate %d: unsupported currency %q: must be one of %s", i+1, in.Currency, strings.Join(Currencies, ", "))
+		}
+		if currency == Base {
+			return nil, fmt.Errorf("rate %d: %s is the base currency", i+1, Base)
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(in.Date))
+		if err != nil {
+			return nil, fmt.Errorf("rate %d: invalid date %q: must be YYYY-MM-DD", i+1, in.Date)
+		}
+		if in.Rate <= 0 {
+			return nil, fmt.Errorf("rate %d: rate must be positive", i+1)
+		}
+
+		rates = append(rates, models.FXRate{
+			Date:     date,
+			Currency: currency,
+			Rate:     in.Rate,
+			Source:   source,
+		})
+	}
+	return rates, nil
+}
+
+// ReadFile reads rates from a JSON array of RateInput or a CSV file with
+// date,currency,rate columns (and an optional header row)
+func ReadFile(path string) ([]models.FXRate, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open FX rates file: %v", err)
+	}
+	defer f.Close()
+
+	var inputs []RateInput
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.NewDecoder(f).Decode(&inputs); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+		}
+	} else if inputs, err = readCSV(f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	return Parse(inputs, filepath.Base(path))
+}
+
+// readCSV reads date,currency,rate rows, skipping a header row and blank lines
+func readCSV(r io.Reader) ([]RateInput, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make([]RateInput, 0, len(records))
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "date") {
+			continue
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rate %q", i+1, record[2])
+		}
+		inputs = append(inputs, RateInput{Date: record[0], Currency: record[1], Rate: rate})
+	}
+	return inputs, nil
+}
+
+// ToBase converts prices to the base currency in place, loading rates only
+// when some price is quoted in another currency
+func ToBase(ctx context.Context, db *mongo.Database, prices []models.PricePoint) error {
+	foreign := false
+	for _, p := range prices {
+		if Of(p.Currency) != Base {
+			foreign = true
+			break
+		}
+	}
+	if !foreign {
+		return nil
+	}
+
+	table, err := Load(ctx, db)
+	if err != nil {
+		return err
+	}
+	return table.ConvertPrices(prices, Base)
+}
+
+// ListingsToBase converts listings to the base currency in place at the
+// rates in effect at the given time, loading rates only when needed
+func ListingsToBase(ctx context.Context, db *mongo.Database, listings []models.Listing, at time.Time) error {
+	foreign := false
+	for _, l := range listings {
+		if Of(l.Currency) != Base {
+			foreign = true
+			break
+		}
+	}
+	if !foreign {
+		return nil
+	}
+
+	table, err := Load(ctx, db)
+	if err != nil {
+		return err
+	}
+	return table.ConvertListings(listings, Base, at)
+}
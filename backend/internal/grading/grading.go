@@ -0,0 +1,195 @@
+package grading
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// Graders are the supported grading companies
+var Graders = []string{"PSA", "BGS", "CGC"}
+
+// NormalizeGrader maps a grader name in any case to its canonical form
+func NormalizeGrader(raw string) (string, bool) {
+	upper := strings.ToUpper(strings.TrimSpace(raw))
+	for _, g := range Graders {
+		if g == upper {
+			return g, true
+		}
+	}
+	return "", false
+}
+
+// ParseGrade parses a numeric grade between 1 and 10 in half-point steps
+func ParseGrade(raw string) (float64, error) {
+	grade, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil || grade < 1 || grade > 10 || math.Mod(grade*2, 1) != 0 {
+		return 0, fmt.Errorf("invalid grade %q: must be 1-10 in steps of 0.5", raw)
+	}
+	return grade, nil
+}
+
+// ParseProbabilities parses grade probabilities such as "10:0.2,9:0.5,8:0.3".
+// Probabilities must be non-negative and sum to at most 1; any remainder is
+// treated as the card coming back below the listed grades.
+func ParseProbabilities(raw string) (map[float64]float64, error) {
+	probabilities := make(map[float64]float64)
+	total := 0.0
+	for _, part := range strings.Split(raw, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		gradeStr, probStr, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid probability %q: expected grade:probability", part)
+		}
+		grade, err := ParseGrade(gradeStr)
+		if err != nil {
+			return nil, err
+		}
+		p, err := strconv.ParseFloat(strings.TrimSpace(probStr), 64)
+		if err != nil || p < 0 || p > 1 {
+			return nil, fmt.Errorf("invalid probability %q for grade %v: must be between 0 and 1", probStr, grade)
+		}
+		probabilities[grade] += p
+		total += p
+	}
+
+	if len(probabilities) == 0 {
+		return nil, fmt.Errorf("no grade probabilities given")
+	}
+	if total > 1.0001 {
+		return nil, fmt.Errorf("grade probabilities sum to %.2f, must be at most 1", total)
+	}
+	return probabilities, nil
+}
+
+// IsRaw reports whether a sale or listing is an ungraded copy
+func IsRaw(grader string) bool {
+	return grader == ""
+}
+
+// Ladder summarizes graded sales per grader and grade, best grade first. Each
+// rung's multiple compares its median to the raw median when raw sales exist.
+func Ladder(prices []models.PricePoint) (rungs []models.GradeRung, rawMedian float64) {
+	type key struct {
+		grader string
+		grade  float64
+	}
+	groups := make(map[key][]models.PricePoint)
+	var raw []float64
+	for _, p := range prices {
+		if IsRaw(p.Grader) {
+			raw = append(raw, p.Price)
+			continue
+		}
+		k := key{p.Grader, p.Grade}
+		groups[k] = append(groups[k], p)
+	}
+	rawMedian = median(raw)
+
+	rungs = make([]models.GradeRung, 0, len(groups))
+	for k, sales := range groups {
+		values := make([]float64, len(sales))
+		last := sales[0]
+		for i, s := range sales {
+			values[i] = s.Price
+			if s.Timestamp.After(last.Timestamp) {
+				last = s
+			}
+		}
+
+		rung := models.GradeRung{
+			Grader:      k.grader,
+			Grade:       k.grade,
+			MedianPrice: roundCents(median(values)),
+			LastPrice:   last.Price,
+			LastSale:    last.Timestamp,
+			Sales:       len(sales),
+		}
+		if rawMedian > 0 {
+			rung.Multiple = math.Round(rung.MedianPrice/rawMedian*100) / 100
+		}
+		rungs = append(rungs, rung)
+	}
+
+	sort.Slice(rungs, func(i, j int) bool {
+		if rungs[i].Grader != rungs[j].Grader {
+			return rungs[i].Grader < rungs[j].Grader
+		}
+		return rungs[i].Grade > rungs[j].Grade
+	})
+	return rungs, roundCents(rawMedian)
+}
+
+// ROI estimates the return of buying a raw copy and grading it. Each grade's
+// value comes from the grader's ladder; grades with no observed sales, and
+// the probability mass not assigned to any grade, are valued at the raw price
+// since such a slab is unlikely to sell for more than the raw card.
+func ROI(grader string, rawPrice, fee float64, probabilities map[float64]float64, rungs []models.GradeRung) models.GradingROI {
+	prices := make(map[float64]float64)
+	for _, r := range rungs {
+		if r.Grader == grader {
+			prices[r.Grade] = r.MedianPrice
+		}
+	}
+
+	grades := make([]float64, 0, len(probabilities))
+	for g := range probabilities {
+		grades = append(grades, g)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(grades)))
+
+	result := models.GradingROI{
+		Grader:     grader,
+		RawPrice:   rawPrice,
+		GradingFee: fee,
+		Cost:       roundCents(rawPrice + fee),
+		Outcomes:   make([]models.GradeOutcome, 0, len(grades)+1),
+	}
+
+	expected, assigned := 0.0, 0.0
+	for _, g := range grades {
+		p := probabilities[g]
+		outcome := models.GradeOutcome{Grade: g, Probability: p, Value: rawPrice, Basis: "raw"}
+		if price, ok := prices[g]; ok {
+			outcome.Value, outcome.Basis = price, "observed"
+		}
+		expected += p * outcome.Value
+		assigned += p
+		result.Outcomes = append(result.Outcomes, outcome)
+	}
+	if rest := 1 - assigned; rest > 1e-9 {
+		result.Outcomes = append(result.Outcomes, models.GradeOutcome{Probability: math.Round(rest*10000) / 10000, Value: rawPrice, Basis: "raw"})
+		expected += rest * rawPrice
+	}
+
+	result.ExpectedValue = roundCents(expected)
+	result.ExpectedProfit = roundCents(expected - result.Cost)
+	if result.Cost > 0 {
+		result.ROI = math.Round(result.ExpectedProfit/result.Cost*10000) / 100
+	}
+	return result
+}
+
+// median returns the median of values, which it sorts in place
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// roundCents rounds a price to two decimal places
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/aggregator"
+	"github.com/jamesc159/monmetrics/internal/analytics"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// GetCardSpread returns the daily price spread between marketplaces for a card
+func (h *Handlers) GetCardSpread(w http.ResponseWriter, r *http.Request) {
+	cardID, ok := parseCardID(w, r)
+	if !ok {
+		return
+	}
+
+	window, err := parseTimeWindow(r.URL.Query(), "90d", time.Now())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	prices, err := h.loadPrices(ctx, cardID, window, priceQuery{})
+	if err != nil {
+		fmt.Printf("Error retrieving prices for spread: %v\n", err)
+		http.Error(w, "Error retrieving price history", http.StatusInternalServerError)
+		return
+	}
+
+	spread := analytics.Spread(prices)
+	spread.CardID = cardID
+	spread.TimeRange = window.Label
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(spread)
+}
+
+// GetCardStats returns volatility, drawdown, period returns and ATH/ATL distance for a card
+func (h *Handlers) GetCardStats(w http.ResponseWriter, r *http.Request) {
+	cardID, ok := parseCardID(w, r)
+	if !ok {
+		return
+	}
+
+	window, err := parseTimeWindow(r.URL.Query(), "1y", time.Now())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	card, err := h.loadCard(ctx, cardID)
+	if err != nil {
+		writeCardLookupError(w, err)
+		return
+	}
+
+	// Period returns look back up to a year regardless of the requested range
+	history := window
+	if yearAgo := window.End.AddDate(-1, 0, -7); yearAgo.Before(history.Start) {
+		history.Start = yearAgo
+	}
+
+	candles, err := h.loadDailyCandles(ctx, cardID, history)
+	if err != nil {
+		fmt.Printf("Error retrieving market data for stats: %v\n", err)
+		http.Error(w, "Error retrieving price history", http.StatusInternalServerError)
+		return
+	}
+
+	stats := analytics.CardStats(analytics.ClosesFromMarketData(candles), window.Start, window.End)
+	stats.CardID = cardID
+	stats.TimeRange = window.Label
+	stats.From = window.Start
+	stats.To = window.End
+	stats.AllTimeHigh = card.AllTimeHigh
+	stats.AllTimeLow = card.AllTimeLow
+
+	if stats.CurrentPrice > 0 {
+		if card.AllTimeHigh > 0 {
+			stats.DistanceFromATH = math.Round((stats.CurrentPrice-card.AllTimeHigh)/card.AllTimeHigh*10000) / 100
+		}
+		if card.AllTimeLow > 0 {
+			stats.DistanceFromATL = math.Round((stats.CurrentPrice-card.AllTimeLow)/card.AllTimeLow*10000) / 100
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetCardCorrelations returns the cards whose daily returns move most and least
+// with the given card, plus its beta against the card's game market
+func (h *Handlers) GetCardCorrelations(w http.ResponseWriter, r *http.Request) {
+	cardID, ok := parseCardID(w, r)
+	if !ok {
+		return
+	}
+
+	window, err := parseTimeWindow(r.URL.Query(), "1y", time.Now())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	limit := 5
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 25 {
+			limit = l
+		}
+	}
+
+	// Pairwise comparisons are expensive, so results are cached per card, window and limit
+	cacheKey := fmt.Sprintf("%s|%s|%s|%s|%d", cardID.Hex(), window.Label,
+		aggregator.TruncateDay(window.Start).Format("2006-01-02"),
+		aggregator.TruncateDay(window.End).Format("2006-01-02"), limit)
+	if cached, ok := h.correlations.Get(cacheKey); ok {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(cached)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	target, err := h.loadCard(ctx, cardID)
+	if err != nil {
+		writeCardLookupError(w, err)
+		return
+	}
+
+	cardsCursor, err := h.db.Collection("cards").Find(ctx, bson.M{},
+		options.Find().SetProjection(bson.M{"name": 1, "set": 1, "game": 1, "image_url": 1}))
+	if err != nil {
+		http.Error(w, "Error retrieving cards", http.StatusInternalServerError)
+		return
+	}
+	var cards []models.Card
+	if err := cardsCursor.All(ctx, &cards); err != nil {
+		http.Error(w, "Error decoding cards", http.StatusInternalServerError)
+		return
+	}
+
+	marketCursor, err := h.db.Collection("market_data").Find(ctx, bson.M{
+		"date": bson.M{
+			"$gte": window.Start,
+			"$lte": window.End,
+		},
+	}, options.Find().
+		SetSort(bson.M{"date": 1}).
+		SetProjection(bson.M{"card_id": 1, "date": 1, "close_price": 1}))
+	if err != nil {
+		http.Error(w, "Error retrieving market data", http.StatusInternalServerError)
+		return
+	}
+	var marketData []models.MarketData
+	if err := marketCursor.All(ctx, &marketData); err != nil {
+		http.Error(w, "Error decoding market data", http.StatusInternalServerError)
+		return
+	}
+
+	byCard := make(map[primitive.ObjectID][]models.MarketData)
+	for _, d := range marketData {
+		byCard[d.CardID] = append(byCard[d.CardID], d)
+	}
+	targetCloses := analytics.ClosesFromMarketData(byCard[cardID])
+
+	// Correlate against every other card with enough overlapping history
+	correlated := make([]models.CorrelatedCard, 0)
+	var gameSeries [][]analytics.Point
+	for _, card := range cards {
+		if card.ID == cardID {
+			continue
+		}
+		closes := analytics.ClosesFromMarketData(byCard[card.ID])
+		if card.Game == target.Game {
+			gameSeries = append(gameSeries, closes)
+		}
+
+		x, y := analytics.AlignedReturns(targetCloses, closes)
+		if len(x) < analytics.MinOverlap {
+			continue
+		}
+		correlated = append(correlated, models.CorrelatedCard{
+			CardID:       card.ID,
+			Name:         card.Name,
+			Set:          card.Set,
+			Game:         card.Game,
+			ImageURL:     card.ImageURL,
+			Correlation:  math.Round(analytics.Correlation(x, y)*10000) / 10000,
+			Observations: len(x),
+		})
+	}
+
+	sort.Slice(correlated, func(i, j int) bool {
+		return correlated[i].Correlation > correlated[j].Correlation
+	})
+
+	result := models.CorrelationAnalysis{
+		CardID:          cardID,
+		TimeRange:       window.Label,
+		From:            window.Start,
+		To:              window.End,
+		Market:          target.Game,
+		MostCorrelated:  correlated[:min(limit, len(correlated))],
+		LeastCorrelated: make([]models.CorrelatedCard, 0, limit),
+		ComputedAt:      time.Now().UTC(),
+	}
+	// Never repeat a card already listed as most correlated
+	for i := len(correlated) - 1; i >= len(result.MostCorrelated) && len(result.LeastCorrelated) < limit; i-- {
+		result.LeastCorrelated = append(result.LeastCorrelated, correlated[i])
+	}
+
+	// Beta against an equal-weighted market of the other cards in the same game
+	assetReturns, marketReturns := analytics.AlignedReturns(targetCloses, analytics.MarketSeries(gameSeries))
+	result.BetaObservations = len(assetReturns)
+	if len(assetReturns) >= analytics.MinOverlap {
+		beta := math.Round(analytics.Beta(assetReturns, marketReturns)*10000) / 10000
+		result.Beta = &beta
+	}
+
+	h.correlations.Set(cacheKey, result)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
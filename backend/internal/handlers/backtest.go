@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/jamesc159/monmetrics/internal/backtest"
+	"github.com/jamesc159/monmetrics/internal/indicators"
+	"github.com/jamesc159/monmetrics/internal/middleware"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// BacktestChart simulates entry/exit rules over a saved chart's indicators
+func (h *Handlers) BacktestChart(w http.ResponseWriter, r *http.Request) {
+	claims, ok := r.Context().Value(middleware.ClaimsKey).(*middleware.Claims)
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := primitive.ObjectIDFromHex(claims.UserID)
+	if err != nil {
+		http.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	chartID, err := primitive.ObjectIDFromHex(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "Invalid chart ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.BacktestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := backtest.Validate(req); err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var chart models.SavedChart
+	err = h.db.Collection("saved_charts").FindOne(ctx, bson.M{
+		"_id":     chartID,
+		"user_id": userID, // Users can only backtest their own charts
+	}).Decode(&chart)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Chart not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error retrieving chart", http.StatusInternalServerError)
+		return
+	}
+
+	timeRange := req.TimeRange
+	if timeRange == "" {
+		timeRange = chart.TimeRange
+	}
+	if timeRange == "" {
+		timeRange = "1y"
+	}
+	now := time.Now()
+	start, err := rangeStart(timeRange, now)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	specs := make([]indicators.Spec, 0, len(chart.Indicators))
+	for _, ci := range chart.Indicators {
+		spec, err := indicators.SpecFromChartIndicator(ci)
+		if err != nil {
+			h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+			return
+		}
+		specs = append(specs, spec)
+	}
+
+	candles, err := h.loadVariantCandles(ctx, chart.CardID, chart.VariantID, timeWindow{Label: timeRange, Start: start, End: now})
+	if err != nil {
+		fmt.Printf("Error retrieving market data for backtest: %v\n", err)
+		http.Error(w, "Error retrieving price history", http.StatusInternalServerError)
+		return
+	}
+	if len(candles) < 2 {
+		h.sendError(w, "Not enough price history to backtest", http.StatusUnprocessableEntity, nil)
+		return
+	}
+
+	bars := indicators.BarsFromMarketData(candles)
+	series, err := indicators.Compute(specs, bars)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	result, err := backtest.Run(bars, series, req)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+	result.ChartID = chart.ID.Hex()
+	result.CardID = chart.CardID.Hex()
+	result.TimeRange = timeRange
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
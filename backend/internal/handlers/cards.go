@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -14,6 +15,12 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"github.com/jamesc159/monmetrics/internal/aggregator"
+	"github.com/jamesc159/monmetrics/internal/conditions"
+	"github.com/jamesc159/monmetrics/internal/downsample"
+	"github.com/jamesc159/monmetrics/internal/fx"
+	"github.com/jamesc159/monmetrics/internal/grading"
+	"github.com/jamesc159/monmetrics/internal/indicators"
 	"github.com/jamesc159/monmetrics/internal/models"
 )
 
@@ -88,6 +95,23 @@ func (h *Handlers) SearchCards(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Parse liquidity filters and sort order
+	liquidityFilter, err := parseLiquidityFilter(r.URL.Query())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+	sortOrder, err := parseSearchSort(r.URL.Query().Get("sort"), liquidityFilter)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+	currency, err := parseCurrency(r.URL.Query())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -95,6 +119,9 @@ func (h *Handlers) SearchCards(w http.ResponseWriter, r *http.Request) {
 
 	// Build search filter using the robust method
 	filter := h.buildSearchFilter(query, game, category)
+	for key, cond := range liquidityFilter {
+		filter[key] = cond
+	}
 
 	// Count total results with improved error handling
 	total, err := collection.CountDocuments(ctx, filter)
@@ -118,6 +145,9 @@ func (h *Handlers) SearchCards(w http.ResponseWriter, r *http.Request) {
 				fallbackFilter["name"] = bson.M{"$regex": primitive.Regex{Pattern: cleanQuery, Options: "i"}}
 			}
 		}
+		for key, cond := range liquidityFilter {
+			fallbackFilter[key] = cond
+		}
 
 		// Try the fallback count
 		total, err = collection.CountDocuments(ctx, fallbackFilter)
@@ -139,7 +169,7 @@ func (h *Handlers) SearchCards(w http.ResponseWriter, r *http.Request) {
 	findOptions := options.Find()
 	findOptions.SetSkip(int64(skip))
 	findOptions.SetLimit(int64(limit))
-	findOptions.SetSort(bson.M{"updated_at": -1})
+	findOptions.SetSort(sortOrder)
 
 	// Execute search
 	cursor, err := collection.Find(ctx, filter, findOptions)
@@ -157,6 +187,10 @@ func (h *Handlers) SearchCards(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Error decoding results", http.StatusInternalServerError)
 		return
 	}
+	if err := h.convertCards(ctx, cards, currency); err != nil {
+		h.writeFXError(w, err)
+		return
+	}
 
 	// Build response
 	response := models.SearchResult{
@@ -185,6 +219,12 @@ func (h *Handlers) GetCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	currency, err := parseCurrency(r.URL.Query())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -201,6 +241,16 @@ func (h *Handlers) GetCard(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	cards := []models.Card{card}
+	if err := h.convertCards(ctx, cards, currency); err != nil {
+		h.writeFXError(w, err)
+		return
+	}
+	card = cards[0]
+
+	// Count the view toward popularity; it's buffered and written in the background
+	h.views.Record(objectID)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(card)
 }
@@ -219,66 +269,197 @@ func (h *Handlers) GetCardPrices(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Parse time range
-	timeRange := r.URL.Query().Get("range")
-	if timeRange == "" {
-		timeRange = "30d"
+	// Parse time range (named range or explicit from/to bounds)
+	window, err := parseTimeWindow(r.URL.Query(), "30d", time.Now())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
 	}
 
-	// Calculate start date based on range
-	var startDate time.Time
-	now := time.Now()
+	// Parse optional candle interval
+	interval := r.URL.Query().Get("interval")
+	var bucket func(time.Time) time.Time
+	if interval != "" {
+		var ok bool
+		if bucket, ok = aggregator.BucketFunc(interval); !ok {
+			h.sendError(w, fmt.Sprintf("invalid interval %q: must be one of 1h, 1d, 1w, 1M", interval), http.StatusBadRequest, nil)
+			return
+		}
+	}
 
-	switch timeRange {
-	case "1d":
-		startDate = now.AddDate(0, 0, -1)
-	case "7d":
-		startDate = now.AddDate(0, 0, -7)
-	case "30d":
-		startDate = now.AddDate(0, 0, -30)
-	case "90d":
-		startDate = now.AddDate(0, 0, -90)
-	case "1y":
-		startDate = now.AddDate(-1, 0, 0)
-	case "5y":
-		startDate = now.AddDate(-5, 0, 0)
-	default:
-		startDate = now.AddDate(0, 0, -30) // Default to 30 days
+	// Parse optional downsampling target (points per series)
+	maxPoints := 0
+	if pointsStr := r.URL.Query().Get("points"); pointsStr != "" {
+		maxPoints, err = strconv.Atoi(pointsStr)
+		if err != nil || maxPoints < downsample.MinPoints || maxPoints > 10000 {
+			h.sendError(w, fmt.Sprintf("invalid points %q: must be between %d and 10000", pointsStr, downsample.MinPoints), http.StatusBadRequest, nil)
+			return
+		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
+	// Parse optional marketplace filter and grouping mode
+	source := strings.ToLower(r.URL.Query().Get("source"))
+	if source != "" {
+		if err := validateSource(source); err != nil {
+			h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+			return
+		}
+	}
 
-	// Get price history
-	pricesCollection := h.db.Collection("prices")
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy != "" && groupBy != "source" {
+		h.sendError(w, fmt.Sprintf("invalid group_by %q: must be source", groupBy), http.StatusBadRequest, nil)
+		return
+	}
 
-	filter := bson.M{
-		"card_id": objectID,
-		"timestamp": bson.M{
-			"$gte": startDate,
-			"$lte": now,
-		},
+	// Parse optional card condition (e.g. "near_mint", "LP", "Heavily Played")
+	condition := ""
+	if raw := r.URL.Query().Get("condition"); raw != "" {
+		var ok bool
+		if condition, ok = conditions.Normalize(raw); !ok {
+			h.sendError(w, fmt.Sprintf("invalid condition %q: must be one of %s", raw, strings.Join(conditions.All, ", ")), http.StatusBadRequest, nil)
+			return
+		}
 	}
 
-	// Sort by timestamp
-	findOptions := options.Find().SetSort(bson.M{"timestamp": 1})
+	// Parse optional grading filter; without one only raw copies are returned
+	var grader string
+	var grade float64
+	if raw := r.URL.Query().Get("grader"); raw != "" {
+		var ok bool
+		if grader, ok = grading.NormalizeGrader(raw); !ok {
+			h.sendError(w, fmt.Sprintf("invalid grader %q: must be one of %s", raw, strings.Join(grading.Graders, ", ")), http.StatusBadRequest, nil)
+			return
+		}
+	}
+	if raw := r.URL.Query().Get("grade"); raw != "" {
+		if grader == "" {
+			h.sendError(w, "grade requires grader", http.StatusBadRequest, nil)
+			return
+		}
+		if grade, err = grading.ParseGrade(raw); err != nil {
+			h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+			return
+		}
+	}
+	if grader != "" && condition != "" {
+		h.sendError(w, "condition applies to raw copies and cannot be combined with grader", http.StatusBadRequest, nil)
+		return
+	}
+
+	// Parse optional currency; prices are converted as of each point's date
+	currency, err := parseCurrency(r.URL.Query())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	// Parse optional printing variant; without one the default variant is returned
+	var variantID *primitive.ObjectID
+	if raw := r.URL.Query().Get("variant"); raw != "" {
+		id, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			h.sendError(w, fmt.Sprintf("invalid variant %q", raw), http.StatusBadRequest, nil)
+			return
+		}
+		variantID = &id
+	}
 
-	cursor, err := pricesCollection.Find(ctx, filter, findOptions)
+	// Sales flagged as outliers are hidden unless explicitly requested
+	includeOutliers := false
+	if raw := r.URL.Query().Get("include_outliers"); raw != "" {
+		if includeOutliers, err = strconv.ParseBool(raw); err != nil {
+			h.sendError(w, fmt.Sprintf("invalid include_outliers %q: must be true or false", raw), http.StatusBadRequest, nil)
+			return
+		}
+	}
+
+	// Parse requested indicators (e.g. "sma:20,bollinger:20:2,rsi:14")
+	var indicatorSpecs []indicators.Spec
+	if raw := r.URL.Query().Get("indicators"); raw != "" {
+		indicatorSpecs, err = indicators.ParseSpecs(raw)
+		if err != nil {
+			h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+			return
+		}
+
+		maxIndicators := getMaxIndicators(userTypeFromRequest(r))
+		if len(indicatorSpecs) > maxIndicators {
+			h.sendError(w, fmt.Sprintf("Exceeded maximum indicators limit (%d)", maxIndicators), http.StatusBadRequest, nil)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var variant *models.CardVariant
+	if variantID != nil {
+		v, err := h.loadVariant(ctx, objectID, *variantID)
+		if err != nil {
+			if err == errUnknownVariant {
+				h.sendError(w, fmt.Sprintf("variant %s does not belong to this card", variantID.Hex()), http.StatusBadRequest, nil)
+				return
+			}
+			writeCardLookupError(w, err)
+			return
+		}
+		variant = &v
+	}
+	variantMatch, err := h.variantMatch(ctx, objectID, variant)
 	if err != nil {
 		http.Error(w, "Error retrieving price history", http.StatusInternalServerError)
 		return
 	}
-	defer cursor.Close(ctx)
 
-	var prices []models.PricePoint
-	if err = cursor.All(ctx, &prices); err != nil {
-		http.Error(w, "Error decoding price history", http.StatusInternalServerError)
+	// Get price history
+	prices, err := h.loadPrices(ctx, objectID, window, priceQuery{
+		Source:          source,
+		Grader:          grader,
+		Grade:           grade,
+		IncludeOutliers: includeOutliers,
+		Variant:         variant,
+		Currency:        currency,
+	})
+	if err != nil {
+		if errors.Is(err, fx.ErrNoRate) {
+			h.writeFXError(w, err)
+			return
+		}
+		http.Error(w, "Error retrieving price history", http.StatusInternalServerError)
 		return
 	}
 
+	// Narrow to one condition, converting other conditions' sales when too
+	// few were recorded in it
+	var conditionInfo *models.ConditionEstimate
+	if condition != "" {
+		prices, conditionInfo, err = h.pricesForCondition(ctx, objectID, prices, condition)
+		if err != nil {
+			fmt.Printf("Error estimating condition prices: %v\n", err)
+			http.Error(w, "Error retrieving price history", http.StatusInternalServerError)
+			return
+		}
+	}
+
 	// Get current listings
+	listingsFilter := bson.M{"card_id": objectID, "variant_id": variantMatch}
+	if source != "" {
+		listingsFilter["source"] = source
+	}
+	if condition != "" {
+		listingsFilter["condition"] = condition
+	}
+	if grader != "" {
+		listingsFilter["grader"] = grader
+		if grade > 0 {
+			listingsFilter["grade"] = grade
+		}
+	} else {
+		listingsFilter["grader"] = bson.M{"$in": bson.A{nil, ""}}
+	}
 	listingsCollection := h.db.Collection("listings")
-	listingsCursor, err := listingsCollection.Find(ctx, bson.M{"card_id": objectID})
+	listingsCursor, err := listingsCollection.Find(ctx, listingsFilter)
 	if err != nil {
 		// Log error but continue - listings are optional
 		fmt.Printf("Warning: Could not retrieve listings: %v\n", err)
@@ -292,38 +473,197 @@ func (h *Handlers) GetCardPrices(w http.ResponseWriter, r *http.Request) {
 			listings = []models.Listing{} // Ensure we have an empty slice
 		}
 	}
+	if currency != "" {
+		table, err := h.loadFX(ctx)
+		if err == nil {
+			err = table.ConvertListings(listings, currency, time.Now())
+		}
+		if err != nil {
+			h.writeFXError(w, err)
+			return
+		}
+	}
 
-	// Get market data
-	marketDataCollection := h.db.Collection("market_data")
-	marketCursor, err := marketDataCollection.Find(ctx, bson.M{
-		"card_id": objectID,
-		"date": bson.M{
-			"$gte": startDate,
-			"$lte": now,
-		},
-	})
-
-	var marketData []models.MarketData
+	// Get market data (raw Near Mint copies aggregated across all sources).
+	// market_data only covers the default variant, so other variants are
+	// rolled up the same way from their prices.
+	marketData, err := h.loadVariantMarketData(ctx, objectID, variant, window)
 	if err != nil {
 		fmt.Printf("Warning: Could not retrieve market data: %v\n", err)
 		marketData = []models.MarketData{} // Ensure we have an empty slice
-	} else {
-		defer marketCursor.Close(ctx)
-		if err = marketCursor.All(ctx, &marketData); err != nil {
-			fmt.Printf("Warning: Could not decode market data: %v\n", err)
-			marketData = []models.MarketData{} // Ensure we have an empty slice
+	}
+	if currency != "" {
+		table, err := h.loadFX(ctx)
+		if err == nil {
+			err = table.ConvertCandles(marketData, currency)
+		}
+		if err != nil {
+			h.writeFXError(w, err)
+			return
+		}
+	}
+
+	// Candles follow market_data in covering Near Mint copies unless another
+	// condition or a grade was asked for
+	candlePrices := prices
+	if condition == "" && grader == "" {
+		candlePrices = conditions.Filter(prices, conditions.NearMint)
+	}
+
+	// Resample raw prices into candles when an interval is requested
+	var candles []models.MarketData
+	if bucket != nil {
+		candles = aggregator.BuildCandles(objectID, candlePrices, bucket)
+	}
+
+	// Compute indicators from requested candles or daily OHLC when available.
+	// Close-only indicators fall back to raw prices; OHLC indicators stay empty
+	// without market data.
+	indicatorSeries := map[string][]models.IndicatorPoint{}
+	if len(indicatorSpecs) > 0 {
+		bars := indicators.BarsFromMarketData(marketData)
+		switch {
+		case bucket != nil:
+			bars = indicators.BarsFromMarketData(candles)
+		case source != "" || condition != "" || grader != "" || includeOutliers:
+			// market_data is raw Near Mint copies across all sources without
+			// outliers, so roll the filtered prices up instead
+			bars = indicators.BarsFromMarketData(aggregator.BuildCandles(objectID, candlePrices, aggregator.TruncateDay))
+		}
+
+		if len(bars) > 0 {
+			indicatorSeries, err = indicators.Compute(indicatorSpecs, bars)
+		} else {
+			// Decided per indicator, so one OHLC indicator doesn't blank the rest
+			closeOnly, ohlc := indicators.SplitOHLC(indicatorSpecs)
+			indicatorSeries, err = indicators.Compute(closeOnly, indicators.BarsFromPrices(candlePrices))
+			if err == nil {
+				var empty map[string][]models.IndicatorPoint
+				empty, err = indicators.Compute(ohlc, nil)
+				for key, series := range empty {
+					indicatorSeries[key] = series
+				}
+			}
+		}
+		if err != nil {
+			h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+			return
+		}
+	}
+
+	// Downsample after indicators so they are computed at full resolution
+	var downsampling *models.DownsampleInfo
+	if maxPoints > 0 {
+		downsampling = &models.DownsampleInfo{
+			Method:             "lttb",
+			MaxPoints:          maxPoints,
+			OriginalPrices:     len(prices),
+			OriginalMarketData: len(marketData),
+			OriginalCandles:    len(candles),
+		}
+
+		prices = downsample.Prices(prices, maxPoints)
+		marketData = downsample.Candles(marketData, maxPoints)
+		if candles != nil {
+			candles = downsample.Candles(candles, maxPoints)
+		}
+		downsampling.Applied = len(prices) < downsampling.OriginalPrices ||
+			len(marketData) < downsampling.OriginalMarketData ||
+			len(candles) < downsampling.OriginalCandles
+
+		for key, series := range indicatorSeries {
+			indicatorSeries[key] = downsample.Indicator(series, maxPoints)
+			if len(indicatorSeries[key]) < len(series) {
+				downsampling.Applied = true
+			}
 		}
 	}
 
 	// Build response
 	response := map[string]interface{}{
-		"prices":      prices,
 		"listings":    listings,
 		"market_data": marketData,
+		"indicators":  indicatorSeries,
 		"card_id":     objectID.Hex(),
-		"time_range":  timeRange,
+		"time_range":  window.Label,
+		"from":        window.Start,
+		"to":          window.End,
+	}
+	if groupBy == "source" {
+		response["prices_by_source"] = groupPricesBySource(prices)
+	} else {
+		response["prices"] = prices
+	}
+	if source != "" {
+		response["source"] = source
+	}
+	if includeOutliers {
+		response["include_outliers"] = true
+	}
+	if conditionInfo != nil {
+		response["condition"] = conditionInfo
+	}
+	if grader != "" {
+		response["grader"] = grader
+		if grade > 0 {
+			response["grade"] = grade
+		}
+	}
+	if variant != nil {
+		response["variant"] = variant
+	}
+	if currency != "" {
+		response["currency"] = currency
+	}
+	if bucket != nil {
+		response["interval"] = interval
+		response["candles"] = candles
+	}
+	if downsampling != nil {
+		response["downsampling"] = downsampling
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// minConditionPoints is the fewest sales in a condition before its series is
+// used as-is rather than estimated from other conditions
+const minConditionPoints = 10
+
+// pricesForCondition returns the card's sales in the given condition, or all
+// sales converted to that condition via the game's multipliers when sparse
+func (h *Handlers) pricesForCondition(ctx context.Context, cardID primitive.ObjectID, prices []models.PricePoint, condition string) ([]models.PricePoint, *models.ConditionEstimate, error) {
+	observed := conditions.Filter(prices, condition)
+
+	card, err := h.loadCard(ctx, cardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	multipliers, err := conditions.Load(ctx, h.db, card.Game)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &models.ConditionEstimate{
+		Condition:      condition,
+		ObservedPoints: len(observed),
+		Multiplier:     multipliers[condition].Multiplier,
+		Basis:          multipliers[condition].Basis,
+	}
+	if len(observed) >= minConditionPoints || len(observed) == len(prices) {
+		return observed, info, nil
+	}
+
+	info.Estimated = true
+	return conditions.Estimate(prices, condition, multipliers), info, nil
+}
+
+// groupPricesBySource splits a price series into one series per marketplace
+func groupPricesBySource(prices []models.PricePoint) map[string][]models.PricePoint {
+	grouped := make(map[string][]models.PricePoint)
+	for _, p := range prices {
+		grouped[p.Source] = append(grouped[p.Source], p)
+	}
+	return grouped
+}
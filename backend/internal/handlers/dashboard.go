@@ -96,6 +96,18 @@ func (h *Handlers) SaveChart(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Charts track the card's default variant unless another is chosen
+	if req.VariantID != nil {
+		if _, err := h.loadVariant(ctx, req.CardID, *req.VariantID); err != nil {
+			if err == errUnknownVariant {
+				h.sendError(w, fmt.Sprintf("variant %s does not belong to this card", req.VariantID.Hex()), http.StatusBadRequest, nil)
+				return
+			}
+			writeCardLookupError(w, err)
+			return
+		}
+	}
+
 	// Set user ID and timestamps
 	req.UserID = userID
 	req.CreatedAt = time.Now().UTC()
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/conditions"
+	"github.com/jamesc159/monmetrics/internal/deals"
+)
+
+const (
+	defaultDealsLimit       = 50
+	maxDealsLimit           = 200
+	defaultDealsMinDiscount = 10
+)
+
+// GetDeals lists listings priced below the card's fair value, or below what
+// reselling at the cheapest comparable ask on another marketplace nets, after
+// estimated fees and shipping. Deals are filterable by game, condition, type
+// and minimum discount, and cached per game, condition and fee schedule.
+func (h *Handlers) GetDeals(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	condition := ""
+	if raw := query.Get("condition"); raw != "" {
+		var ok bool
+		if condition, ok = conditions.Normalize(raw); !ok {
+			h.sendError(w, fmt.Sprintf("invalid condition %q: must be one of %s", raw, strings.Join(conditions.All, ", ")), http.StatusBadRequest, nil)
+			return
+		}
+	}
+
+	dealType := query.Get("type")
+	if dealType != "" && dealType != deals.FairValue && dealType != deals.Arbitrage {
+		h.sendError(w, fmt.Sprintf("invalid type %q: must be one of %s", dealType, strings.Join(deals.Types, ", ")), http.StatusBadRequest, nil)
+		return
+	}
+
+	minDiscount := float64(defaultDealsMinDiscount)
+	if raw := query.Get("min_discount"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed < 0 || parsed >= 100 {
+			h.sendError(w, fmt.Sprintf("invalid min_discount %q: must be a percentage from 0 to below 100", raw), http.StatusBadRequest, nil)
+			return
+		}
+		minDiscount = parsed
+	}
+
+	limit := defaultDealsLimit
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxDealsLimit {
+			h.sendError(w, fmt.Sprintf("invalid limit %q: must be between 1 and %d", raw, maxDealsLimit), http.StatusBadRequest, nil)
+			return
+		}
+		limit = parsed
+	}
+
+	currency, err := parseCurrency(query)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	fees, err := deals.ParseSchedule(h.config.DealsFees)
+	if err != nil {
+		fmt.Printf("Error parsing DEALS_FEES: %v\n", err)
+		http.Error(w, "Invalid deal fee configuration", http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	game := query.Get("game")
+	now := time.Now().UTC()
+	cacheKey := fmt.Sprintf("%s|%s|%s", game, condition, h.config.DealsFees)
+	found, ok := h.deals.Get(cacheKey)
+	if !ok {
+		found, err = deals.Find(ctx, h.db, deals.Options{
+			Game:          game,
+			Condition:     condition,
+			Fees:          fees,
+			MinConfidence: h.config.DealsMinConfidence,
+		}, now)
+		if err != nil {
+			fmt.Printf("Error finding deals: %v\n", err)
+			http.Error(w, "Error finding deals", http.StatusInternalServerError)
+			return
+		}
+		h.deals.Set(cacheKey, found)
+	}
+
+	selected := deals.Select(found, deals.Criteria{Type: dealType, MinDiscount: minDiscount, Limit: limit})
+	if currency != "" {
+		table, err := h.loadFX(ctx)
+		if err == nil {
+			err = table.ConvertDeals(selected, currency, now)
+		}
+		if err != nil {
+			h.writeFXError(w, err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"deals":        selected,
+		"count":        len(selected),
+		"min_discount": minDiscount,
+	})
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/ev"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// GetCardEV values a sealed product from current prices of the singles in its
+// set, using its recorded packs and pull rates. drivers limits the top-value
+// singles returned (default 10, max 50).
+func (h *Handlers) GetCardEV(w http.ResponseWriter, r *http.Request) {
+	cardID, ok := parseCardID(w, r)
+	if !ok {
+		return
+	}
+
+	drivers := ev.DefaultDrivers
+	if raw := r.URL.Query().Get("drivers"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 50 {
+			h.sendError(w, fmt.Sprintf("invalid drivers %q: must be between 1 and 50", raw), http.StatusBadRequest, nil)
+			return
+		}
+		drivers = n
+	}
+
+	currency, err := parseCurrency(r.URL.Query())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	product, err := h.loadCard(ctx, cardID)
+	if err != nil {
+		writeCardLookupError(w, err)
+		return
+	}
+	if product.Category != "sealed" {
+		h.sendError(w, "expected value is only available for sealed products", http.StatusBadRequest, nil)
+		return
+	}
+	if product.Contents == nil {
+		h.sendError(w, ev.ErrNoContents.Error(), http.StatusNotFound, nil)
+		return
+	}
+
+	set := product.Contents.Set
+	if set == "" {
+		set = product.Set
+	}
+	cursor, err := h.db.Collection("cards").Find(ctx,
+		bson.M{"game": product.Game, "set": set, "category": "card"},
+		options.Find().SetProjection(bson.M{"name": 1, "rarity": 1, "current_price": 1, "fair_value": 1}))
+	if err != nil {
+		http.Error(w, "Error retrieving set singles", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	singles := make([]models.Card, 0)
+	if err := cursor.All(ctx, &singles); err != nil {
+		http.Error(w, "Error retrieving set singles", http.StatusInternalServerError)
+		return
+	}
+
+	result, err := ev.Compute(product, singles, drivers)
+	if err != nil {
+		http.Error(w, "Error computing expected value", http.StatusInternalServerError)
+		return
+	}
+
+	if currency != "" {
+		table, err := h.loadFX(ctx)
+		if err == nil {
+			err = table.ConvertExpectedValue(&result, currency, time.Now())
+		}
+		if err != nil {
+			h.writeFXError(w, err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// SetProductContents records a sealed product's packs per box, pack slots and
+// pull rates per rarity
+func (h *Handlers) SetProductContents(w http.ResponseWriter, r *http.Request) {
+	cardID, ok := parseCardID(w, r)
+	if !ok {
+		return
+	}
+
+	var contents models.SealedContents
+	if err := json.NewDecoder(r.Body).Decode(&contents); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := ev.Validate(contents); err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	product, err := h.loadCard(ctx, cardID)
+	if err != nil {
+		writeCardLookupError(w, err)
+		return
+	}
+	if product.Category != "sealed" {
+		h.sendError(w, "contents can only be recorded for sealed products", http.StatusBadRequest, nil)
+		return
+	}
+
+	contents.UpdatedAt = time.Now().UTC()
+	_, err = h.db.Collection("cards").UpdateOne(ctx,
+		bson.M{"_id": cardID},
+		bson.M{"$set": bson.M{"contents": contents}},
+	)
+	if err != nil {
+		http.Error(w, "Error saving contents", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(contents)
+}
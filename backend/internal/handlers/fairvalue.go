@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/fairvalue"
+)
+
+// GetCardFairValue estimates what a Near Mint raw copy of the card is worth
+// from recency- and volume-weighted sales, capped by the cheapest asks, with
+// a range, a confidence score and per-condition values
+func (h *Handlers) GetCardFairValue(w http.ResponseWriter, r *http.Request) {
+	cardID, ok := parseCardID(w, r)
+	if !ok {
+		return
+	}
+
+	currency, err := parseCurrency(r.URL.Query())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	card, err := h.loadCard(ctx, cardID)
+	if err != nil {
+		writeCardLookupError(w, err)
+		return
+	}
+
+	fv, ok, err := h.fairValues().ForCard(ctx, card)
+	if err != nil {
+		fmt.Printf("Error estimating fair value: %v\n", err)
+		http.Error(w, "Error estimating fair value", http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		h.sendError(w, fmt.Sprintf("no sales in the last %d days to estimate fair value from", h.config.FairValueWindowDays), http.StatusNotFound, nil)
+		return
+	}
+
+	if currency != "" {
+		table, err := h.loadFX(ctx)
+		if err == nil {
+			err = table.ConvertFairValue(&fv, currency, fv.ComputedAt)
+		}
+		if err != nil {
+			h.writeFXError(w, err)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"card_id":    cardID.Hex(),
+		"fair_value": fv,
+	})
+}
+
+// fairValues returns a fair value calculator using the configured parameters
+func (h *Handlers) fairValues() *fairvalue.Calculator {
+	return fairvalue.New(h.db, fairvalue.Params{
+		WindowDays:   h.config.FairValueWindowDays,
+		HalfLifeDays: h.config.FairValueHalfLifeDays,
+	})
+}
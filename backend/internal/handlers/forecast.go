@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/analytics"
+	"github.com/jamesc159/monmetrics/internal/forecast"
+)
+
+const (
+	// defaultForecastHorizon is the fixed horizon for free users, in days
+	defaultForecastHorizon = 30
+	// maxForecastHorizon caps the horizon paid users may request, in days
+	maxForecastHorizon = 365
+	// forecastHistoryYears is how much daily history each model is fitted on
+	forecastHistoryYears = 1
+)
+
+// GetCardForecast projects a card's daily closing price forward with
+// confidence bands. Paid users may pick the model and horizon; free users
+// always get the default model over the default horizon.
+func (h *Handlers) GetCardForecast(w http.ResponseWriter, r *http.Request) {
+	cardID, ok := parseCardID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	model := forecast.DefaultModel
+	horizon := defaultForecastHorizon
+
+	if raw := query.Get("model"); raw != "" {
+		model = raw
+	}
+	if raw := query.Get("horizon"); raw != "" {
+		days, err := parseHorizon(raw)
+		if err != nil {
+			h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+			return
+		}
+		horizon = days
+	}
+
+	if userTypeFromRequest(r) != "paid" && (model != forecast.DefaultModel || horizon != defaultForecastHorizon) {
+		h.sendError(w, "Custom forecast models and horizons require a paid account", http.StatusForbidden, map[string]interface{}{
+			"default_model":   forecast.DefaultModel,
+			"default_horizon": fmt.Sprintf("%dd", defaultForecastHorizon),
+		})
+		return
+	}
+
+	if !isKnownForecastModel(model) {
+		h.sendError(w, fmt.Sprintf("Unknown forecast model %q", model), http.StatusBadRequest, map[string]interface{}{
+			"models": forecast.Models(),
+		})
+		return
+	}
+
+	now := time.Now()
+	window := timeWindow{Label: "1y", Start: now.AddDate(-forecastHistoryYears, 0, 0), End: now}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if _, err := h.loadCard(ctx, cardID); err != nil {
+		writeCardLookupError(w, err)
+		return
+	}
+
+	candles, err := h.loadDailyCandles(ctx, cardID, window)
+	if err != nil {
+		fmt.Printf("Error retrieving market data for forecast: %v\n", err)
+		http.Error(w, "Error retrieving price history", http.StatusInternalServerError)
+		return
+	}
+
+	closes := analytics.ClosesFromMarketData(candles)
+	if len(closes) == 0 {
+		h.sendError(w, "Not enough price history to forecast", http.StatusUnprocessableEntity, nil)
+		return
+	}
+
+	result, err := forecast.Forecast(model, closes, horizon)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusUnprocessableEntity, nil)
+		return
+	}
+	result.CardID = cardID
+	result.GeneratedAt = time.Now().UTC()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// parseHorizon parses a forecast horizon such as "30d" (or a bare day count)
+func parseHorizon(raw string) (int, error) {
+	days, err := strconv.Atoi(strings.TrimSuffix(raw, "d"))
+	if err != nil || days < 1 || days > maxForecastHorizon {
+		return 0, fmt.Errorf("invalid horizon %q: expected 1d-%dd", raw, maxForecastHorizon)
+	}
+	return days, nil
+}
+
+// isKnownForecastModel reports whether name is a supported forecast model
+func isKnownForecastModel(name string) bool {
+	for _, m := range forecast.Models() {
+		if m == name {
+			return true
+		}
+	}
+	return false
+}
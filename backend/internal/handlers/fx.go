@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/fx"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// fxTableKey is the cache key for the full FX rate table
+const fxTableKey = "all"
+
+// ImportFXRates upserts dated FX rates posted as a JSON array of
+// {"date":"2024-01-31","currency":"EUR","rate":0.92}, one unit of the base
+// currency (USD) expressed in the given currency
+func (h *Handlers) ImportFXRates(w http.ResponseWriter, r *http.Request) {
+	var inputs []fx.RateInput
+	if err := json.NewDecoder(r.Body).Decode(&inputs); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if len(inputs) == 0 {
+		h.sendError(w, "at least one rate is required", http.StatusBadRequest, nil)
+		return
+	}
+
+	rates, err := fx.Parse(inputs, "admin")
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	saved, err := fx.Save(ctx, h.db, rates)
+	if err != nil {
+		fmt.Printf("Error saving FX rates: %v\n", err)
+		http.Error(w, "Error saving FX rates", http.StatusInternalServerError)
+		return
+	}
+	h.fxTables.Delete(fxTableKey)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"received": len(rates),
+		"saved":    saved,
+	})
+}
+
+// parseCurrency reads the optional currency parameter; empty means prices
+// stay in the stored currency (USD)
+func parseCurrency(query url.Values) (string, error) {
+	raw := query.Get("currency")
+	if raw == "" {
+		return "", nil
+	}
+	currency, ok := fx.Normalize(raw)
+	if !ok {
+		return "", fmt.Errorf("invalid currency %q: must be one of %s", raw, strings.Join(fx.Currencies, ", "))
+	}
+	return currency, nil
+}
+
+// loadFX returns the FX rate table, cached briefly since rates change rarely
+func (h *Handlers) loadFX(ctx context.Context) (*fx.Table, error) {
+	if table, ok := h.fxTables.Get(fxTableKey); ok {
+		return table, nil
+	}
+	table, err := fx.Load(ctx, h.db)
+	if err != nil {
+		return nil, err
+	}
+	h.fxTables.Set(fxTableKey, table)
+	return table, nil
+}
+
+// convertPrices converts prices in place to the currency (the base currency
+// when empty), skipping the rate lookup when nothing needs converting
+func (h *Handlers) convertPrices(ctx context.Context, prices []models.PricePoint, currency string) error {
+	currency = fx.Of(currency)
+	needed := false
+	for _, p := range prices {
+		if fx.Of(p.Currency) != currency {
+			needed = true
+			break
+		}
+	}
+	if !needed {
+		return nil
+	}
+
+	table, err := h.loadFX(ctx)
+	if err != nil {
+		return err
+	}
+	return table.ConvertPrices(prices, currency)
+}
+
+// convertCards converts cards' stored USD price summaries in place
+func (h *Handlers) convertCards(ctx context.Context, cards []models.Card, currency string) error {
+	if currency == "" || currency == fx.Base {
+		return nil
+	}
+
+	table, err := h.loadFX(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range cards {
+		if err := table.ConvertCard(&cards[i], currency, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFXError maps a conversion failure to a 422 when rates are missing, or a 500
+func (h *Handlers) writeFXError(w http.ResponseWriter, err error) {
+	if errors.Is(err, fx.ErrNoRate) {
+		h.sendError(w, err.Error(), http.StatusUnprocessableEntity, nil)
+		return
+	}
+	fmt.Printf("Error converting currency: %v\n", err)
+	http.Error(w, "Error converting currency", http.StatusInternalServerError)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/grading"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// GetGradeLadder returns a card's median sale per grader and grade alongside
+// the raw median, so the premium for each grade is visible at a glance
+func (h *Handlers) GetGradeLadder(w http.ResponseWriter, r *http.Request) {
+	cardID, ok := parseCardID(w, r)
+	if !ok {
+		return
+	}
+
+	window, err := parseTimeWindow(r.URL.Query(), "1y", time.Now())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if _, err := h.loadCard(ctx, cardID); err != nil {
+		writeCardLookupError(w, err)
+		return
+	}
+
+	prices, err := h.loadPrices(ctx, cardID, window, priceQuery{AllGrades: true})
+	if err != nil {
+		http.Error(w, "Error retrieving price history", http.StatusInternalServerError)
+		return
+	}
+
+	rungs, rawMedian := grading.Ladder(prices)
+	ladder := models.GradeLadder{
+		CardID:    cardID,
+		TimeRange: window.Label,
+		From:      window.Start,
+		To:        window.End,
+		RawPrice:  rawMedian,
+		Rungs:     rungs,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ladder)
+}
+
+// GetGradingROI estimates whether grading a raw copy pays off: the raw price
+// plus grading fee against the probability-weighted value of the possible
+// grades. fee and probabilities (e.g. "10:0.2,9:0.5,8:0.3") override the
+// configured assumptions.
+func (h *Handlers) GetGradingROI(w http.ResponseWriter, r *http.Request) {
+	cardID, ok := parseCardID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+
+	grader := "PSA"
+	if raw := query.Get("grader"); raw != "" {
+		if grader, ok = grading.NormalizeGrader(raw); !ok {
+			h.sendError(w, fmt.Sprintf("invalid grader %q: must be one of %s", raw, strings.Join(grading.Graders, ", ")), http.StatusBadRequest, nil)
+			return
+		}
+	}
+
+	fee := h.config.GradingFee
+	if raw := query.Get("fee"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed < 0 {
+			h.sendError(w, fmt.Sprintf("invalid fee %q: must be a non-negative number", raw), http.StatusBadRequest, nil)
+			return
+		}
+		fee = parsed
+	}
+
+	rawProbabilities := h.config.GradingProbabilities
+	if raw := query.Get("probabilities"); raw != "" {
+		rawProbabilities = raw
+	}
+	probabilities, err := grading.ParseProbabilities(rawProbabilities)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	window, err := parseTimeWindow(query, "1y", time.Now())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	card, err := h.loadCard(ctx, cardID)
+	if err != nil {
+		writeCardLookupError(w, err)
+		return
+	}
+
+	prices, err := h.loadPrices(ctx, cardID, window, priceQuery{AllGrades: true})
+	if err != nil {
+		http.Error(w, "Error retrieving price history", http.StatusInternalServerError)
+		return
+	}
+
+	// The card's maintained current price is raw; fall back to the window's raw median
+	rungs, rawMedian := grading.Ladder(prices)
+	rawPrice := card.CurrentPrice
+	if rawPrice <= 0 {
+		rawPrice = rawMedian
+	}
+	if rawPrice <= 0 {
+		h.sendError(w, "No raw price available for this card", http.StatusUnprocessableEntity, nil)
+		return
+	}
+
+	result := grading.ROI(grader, rawPrice, fee, probabilities, rungs)
+	result.CardID = cardID
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
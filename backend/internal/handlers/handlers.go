@@ -10,19 +10,40 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 
 	"github.com/jamesc159/monmetrics/configs"
+	"github.com/jamesc159/monmetrics/internal/cache"
+	"github.com/jamesc159/monmetrics/internal/fx"
+	"github.com/jamesc159/monmetrics/internal/middleware"
+	"github.com/jamesc159/monmetrics/internal/models"
+	"github.com/jamesc159/monmetrics/internal/popularity"
 )
 
 // Handlers holds the database and configuration for all handler methods
 type Handlers struct {
 	db     *mongo.Database
 	config *configs.Config
+
+	// Caches for expensive analytics responses
+	correlations *cache.TTL[models.CorrelationAnalysis]
+	leaderboards *cache.TTL[models.Leaderboards]
+	deals        *cache.TTL[[]models.Deal]
+
+	// FX rates, reloaded periodically and after imports
+	fxTables *cache.TTL[*fx.Table]
+
+	// Card page views, flushed to card_views in the background
+	views *popularity.Views
 }
 
 // New creates a new Handlers instance
-func New(db *mongo.Database, config *configs.Config) *Handlers {
+func New(db *mongo.Database, config *configs.Config, views *popularity.Views) *Handlers {
 	return &Handlers{
-		db:     db,
-		config: config,
+		db:           db,
+		config:       config,
+		views:        views,
+		correlations: cache.NewTTL[models.CorrelationAnalysis](time.Hour),
+		leaderboards: cache.NewTTL[models.Leaderboards](5 * time.Minute),
+		deals:        cache.NewTTL[[]models.Deal](5 * time.Minute),
+		fxTables:     cache.NewTTL[*fx.Table](10 * time.Minute),
 	}
 }
 
@@ -58,6 +79,14 @@ func (h *Handlers) Health(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// userTypeFromRequest returns the caller's user type, defaulting to "free" for anonymous requests
+func userTypeFromRequest(r *http.Request) string {
+	if claims, ok := r.Context().Value(middleware.ClaimsKey).(*middleware.Claims); ok && claims.UserType != "" {
+		return claims.UserType
+	}
+	return "free"
+}
+
 // sendError sends a standardized error response
 func (h *Handlers) sendError(w http.ResponseWriter, message string, statusCode int, data map[string]interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// GetIndices lists the market benchmark indices, optionally filtered by game, set and category
+func (h *Handlers) GetIndices(w http.ResponseWriter, r *http.Request) {
+	filter := bson.M{}
+	if game := r.URL.Query().Get("game"); game != "" {
+		filter["game"] = game
+	}
+	if set := r.URL.Query().Get("set"); set != "" {
+		filter["set"] = set
+	}
+	if category := r.URL.Query().Get("category"); category != "" {
+		filter["category"] = category
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "game", Value: 1}, {Key: "set", Value: 1}, {Key: "category", Value: 1}})
+	cursor, err := h.db.Collection("indices").Find(ctx, filter, findOptions)
+	if err != nil {
+		fmt.Printf("Error retrieving indices: %v\n", err)
+		http.Error(w, "Error retrieving indices", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	indexList := make([]models.MarketIndex, 0)
+	if err := cursor.All(ctx, &indexList); err != nil {
+		fmt.Printf("Error decoding indices: %v\n", err)
+		http.Error(w, "Error decoding indices", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(indexList)
+}
+
+// GetIndexHistory returns the daily values of an index, addressed by ID or slug
+func (h *Handlers) GetIndexHistory(w http.ResponseWriter, r *http.Request) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		http.Error(w, "Index ID required", http.StatusBadRequest)
+		return
+	}
+
+	window, err := parseTimeWindow(r.URL.Query(), "1y", time.Now())
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	lookup := bson.M{"slug": idStr}
+	if objectID, err := primitive.ObjectIDFromHex(idStr); err == nil {
+		lookup = bson.M{"_id": objectID}
+	}
+
+	var index models.MarketIndex
+	if err := h.db.Collection("indices").FindOne(ctx, lookup).Decode(&index); err != nil {
+		if err == mongo.ErrNoDocuments {
+			http.Error(w, "Index not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Error retrieving index", http.StatusInternalServerError)
+		return
+	}
+
+	cursor, err := h.db.Collection("index_values").Find(ctx, bson.M{
+		"index_id": index.ID,
+		"date": bson.M{
+			"$gte": window.Start,
+			"$lte": window.End,
+		},
+	}, options.Find().SetSort(bson.M{"date": 1}))
+	if err != nil {
+		http.Error(w, "Error retrieving index history", http.StatusInternalServerError)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	values := make([]models.IndexValue, 0)
+	if err := cursor.All(ctx, &values); err != nil {
+		http.Error(w, "Error decoding index history", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"index":      index,
+		"values":     values,
+		"time_range": window.Label,
+		"from":       window.Start,
+		"to":         window.End,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
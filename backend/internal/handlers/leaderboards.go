@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+	"github.com/jamesc159/monmetrics/internal/movers"
+)
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 50
+)
+
+// leaderboardWindows are the lookbacks the leaderboard endpoint accepts
+var leaderboardWindows = map[string]bool{"24h": true, "7d": true, "30d": true}
+
+// GetLeaderboards returns the top gainers, losers and most traded cards over
+// a window, optionally narrowed to a game and category. Boards are cached per
+// window and filter at the maximum size and trimmed to the requested limit.
+func (h *Handlers) GetLeaderboards(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	label := query.Get("window")
+	if label == "" {
+		label = "24h"
+	}
+	if !leaderboardWindows[label] {
+		h.sendError(w, fmt.Sprintf("invalid window %q: must be one of 24h, 7d, 30d", label), http.StatusBadRequest, nil)
+		return
+	}
+	window, err := movers.ParseWindow(label)
+	if err != nil {
+		h.sendError(w, err.Error(), http.StatusBadRequest, nil)
+		return
+	}
+
+	limit := defaultLeaderboardLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > maxLeaderboardLimit {
+			h.sendError(w, fmt.Sprintf("invalid limit %q: must be between 1 and %d", limitStr, maxLeaderboardLimit), http.StatusBadRequest, nil)
+			return
+		}
+	}
+
+	filter := movers.LeaderboardFilter{
+		Game:      query.Get("game"),
+		Category:  query.Get("category"),
+		MinVolume: h.config.MoversMinVolume,
+		Limit:     maxLeaderboardLimit,
+	}
+
+	cacheKey := fmt.Sprintf("%s|%s|%s", window.Label, filter.Game, filter.Category)
+	boards, ok := h.leaderboards.Get(cacheKey)
+	if !ok {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+
+		boards, err = movers.Leaderboard(ctx, h.db, window, time.Now().UTC(), filter)
+		if err != nil {
+			fmt.Printf("Error building leaderboards: %v\n", err)
+			http.Error(w, "Error building leaderboards", http.StatusInternalServerError)
+			return
+		}
+		h.leaderboards.Set(cacheKey, boards)
+	}
+
+	boards.Gainers = trimEntries(boards.Gainers, limit)
+	boards.Losers = trimEntries(boards.Losers, limit)
+	boards.MostTraded = trimEntries(boards.MostTraded, limit)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(boards)
+}
+
+// trimEntries returns at most limit leaderboard rows
+func trimEntries(entries []models.LeaderboardEntry, limit int) []models.LeaderboardEntry {
+	if len(entries) > limit {
+		return entries[:limit]
+	}
+	return entries
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/aggregator"
+	"github.com/jamesc159/monmetrics/internal/conditions"
+	"github.com/jamesc159/monmetrics/internal/models"
+	"github.com/jamesc159/monmetrics/internal/variants"
+)
+
+// errUnknownVariant is returned when a variant ID doesn't belong to the card
+var errUnknownVariant = errors.New("variant not found for card")
+
+// parseCardID extracts the {id} path value, writing a 400 response when it is invalid
+func parseCardID(w http.ResponseWriter, r *http.Request) (primitive.ObjectID, bool) {
+	idStr := r.PathValue("id")
+	if idStr == "" {
+		http.Error(w, "Card ID required", http.StatusBadRequest)
+		return primitive.NilObjectID, false
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(idStr)
+	if err != nil {
+		http.Error(w, "Invalid card ID", http.StatusBadRequest)
+		return primitive.NilObjectID, false
+	}
+
+	return objectID, true
+}
+
+// priceQuery narrows which of a card's sales loadPrices returns
+type priceQuery struct {
+	Source          string  // Empty matches every marketplace
+	Grader          string  // Empty matches raw copies only
+	Grade           float64 // With Grader, zero matches every grade
+	AllGrades       bool    // Match raw and graded copies alike, ignoring Grader
+	IncludeOutliers bool
+	Variant         *models.CardVariant // Nil matches the card's default variant
+	Currency        string              // Prices are converted to it as of each sale; empty means USD
+	Condition       interface{}         // condition filter, e.g. conditions.MatchNearMint(); nil matches every condition
+}
+
+// loadPrices returns a card's price points inside the window, oldest first.
+// By default only raw (ungraded) sales of the default variant not flagged as
+// outliers are returned.
+func (h *Handlers) loadPrices(ctx context.Context, cardID primitive.ObjectID, window timeWindow, query priceQuery) ([]models.PricePoint, error) {
+	variantMatch, err := h.variantMatch(ctx, cardID, query.Variant)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"card_id":    cardID,
+		"variant_id": variantMatch,
+		"timestamp": bson.M{
+			"$gte": window.Start,
+			"$lte": window.End,
+		},
+	}
+	if query.Source != "" {
+		filter["source"] = query.Source
+	}
+	switch {
+	case query.AllGrades:
+	case query.Grader == "":
+		filter["grader"] = bson.M{"$in": bson.A{nil, ""}}
+	default:
+		filter["grader"] = query.Grader
+		if query.Grade > 0 {
+			filter["grade"] = query.Grade
+		}
+	}
+	if !query.IncludeOutliers {
+		filter["outlier"] = bson.M{"$ne": true}
+	}
+	if query.Condition != nil {
+		filter["condition"] = query.Condition
+	}
+
+	cursor, err := h.db.Collection("prices").Find(ctx, filter, options.Find().SetSort(bson.M{"timestamp": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	prices := make([]models.PricePoint, 0)
+	if err := cursor.All(ctx, &prices); err != nil {
+		return nil, err
+	}
+	if err := h.convertPrices(ctx, prices, query.Currency); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// loadMarketData returns a card's daily OHLC documents inside the window, oldest first
+func (h *Handlers) loadMarketData(ctx context.Context, cardID primitive.ObjectID, window timeWindow) ([]models.MarketData, error) {
+	filter := bson.M{
+		"card_id": cardID,
+		"date": bson.M{
+			"$gte": window.Start,
+			"$lte": window.End,
+		},
+	}
+
+	cursor, err := h.db.Collection("market_data").Find(ctx, filter, options.Find().SetSort(bson.M{"date": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	marketData := make([]models.MarketData, 0)
+	if err := cursor.All(ctx, &marketData); err != nil {
+		return nil, err
+	}
+	return marketData, nil
+}
+
+// loadDailyCandles returns daily OHLC for the window, rolling raw Near Mint
+// prices up on the fly when the aggregation job hasn't populated market_data yet
+func (h *Handlers) loadDailyCandles(ctx context.Context, cardID primitive.ObjectID, window timeWindow) ([]models.MarketData, error) {
+	marketData, err := h.loadMarketData(ctx, cardID, window)
+	if err != nil {
+		return nil, err
+	}
+	if len(marketData) > 0 {
+		return marketData, nil
+	}
+
+	prices, err := h.loadPrices(ctx, cardID, window, priceQuery{Condition: conditions.MatchNearMint()})
+	if err != nil {
+		return nil, err
+	}
+	return aggregator.BuildCandles(cardID, prices, aggregator.TruncateDay), nil
+}
+
+// loadVariantCandles returns daily OHLC for one of the card's variants.
+// market_data only covers the default variant, so other variants are rolled
+// up from their raw Near Mint prices the same way.
+func (h *Handlers) loadVariantCandles(ctx context.Context, cardID primitive.ObjectID, variantID *primitive.ObjectID, window timeWindow) ([]models.MarketData, error) {
+	if variantID == nil {
+		return h.loadDailyCandles(ctx, cardID, window)
+	}
+
+	variant, err := h.loadVariant(ctx, cardID, *variantID)
+	if err != nil {
+		return nil, err
+	}
+	if variant.IsDefault {
+		return h.loadDailyCandles(ctx, cardID, window)
+	}
+	return h.loadVariantMarketData(ctx, cardID, &variant, window)
+}
+
+// loadVariantMarketData returns market_data for the card's default variant,
+// and candles rolled up like it from raw Near Mint prices for other variants
+func (h *Handlers) loadVariantMarketData(ctx context.Context, cardID primitive.ObjectID, variant *models.CardVariant, window timeWindow) ([]models.MarketData, error) {
+	if variant == nil || variant.IsDefault {
+		return h.loadMarketData(ctx, cardID, window)
+	}
+
+	prices, err := h.loadPrices(ctx, cardID, window, priceQuery{Variant: variant, Condition: conditions.MatchNearMint()})
+	if err != nil {
+		return nil, err
+	}
+	return aggregator.BuildCandles(cardID, prices, aggregator.TruncateDay), nil
+}
+
+// loadVariant returns one of the card's variants, or errUnknownVariant when
+// the card has no variant with that ID
+func (h *Handlers) loadVariant(ctx context.Context, cardID, variantID primitive.ObjectID) (models.CardVariant, error) {
+	card, err := h.loadCardVariants(ctx, cardID)
+	if err != nil {
+		return models.CardVariant{}, err
+	}
+	variant, ok := variants.Find(card, variantID)
+	if !ok {
+		return models.CardVariant{}, errUnknownVariant
+	}
+	return variant, nil
+}
+
+// variantMatch returns the variant_id condition selecting a variant's
+// documents, falling back to the card's default variant when none is given
+func (h *Handlers) variantMatch(ctx context.Context, cardID primitive.ObjectID, variant *models.CardVariant) (interface{}, error) {
+	if variant != nil {
+		return variants.Match(*variant), nil
+	}
+
+	card, err := h.loadCardVariants(ctx, cardID)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	return variants.MatchDefault(card), nil
+}
+
+// loadCardVariants returns a card document holding only its variants
+func (h *Handlers) loadCardVariants(ctx context.Context, cardID primitive.ObjectID) (models.Card, error) {
+	var card models.Card
+	err := h.db.Collection("cards").FindOne(ctx, bson.M{"_id": cardID},
+		options.FindOne().SetProjection(bson.M{"variants": 1})).Decode(&card)
+	return card, err
+}
+
+// loadCard returns a single card document by ID
+func (h *Handlers) loadCard(ctx context.Context, cardID primitive.ObjectID) (models.Card, error) {
+	var card models.Card
+	err := h.db.Collection("cards").FindOne(ctx, bson.M{"_id": cardID}).Decode(&card)
+	return card, err
+}
+
+// writeCardLookupError maps a card lookup failure to a 404 or 500 response
+func writeCardLookupError(w http.ResponseWriter, err error) {
+	if err == mongo.ErrNoDocuments {
+		http.Error(w, "Card not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, "Error retrieving card", http.StatusInternalServerError)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// searchSorts maps SearchCards sort names to their sort order
+var searchSorts = map[string]bson.D{
+	"updated":      {{Key: "updated_at", Value: -1}},
+	"liquidity":    {{Key: "liquidity.score", Value: -1}, {Key: "updated_at", Value: -1}},
+	"sell_through": {{Key: "liquidity.sell_through_rate", Value: -1}, {Key: "updated_at", Value: -1}},
+	"days_to_sell": {{Key: "liquidity.days_to_sell", Value: 1}, {Key: "updated_at", Value: -1}},
+	"fair_value":   {{Key: "fair_value.value", Value: -1}, {Key: "updated_at", Value: -1}},
+}
+
+// parseLiquidityFilter builds card filter conditions from the min_liquidity,
+// min_sell_through and max_days_to_sell query parameters
+func parseLiquidityFilter(query url.Values) (bson.M, error) {
+	filter := bson.M{}
+
+	if raw := query.Get("min_liquidity"); raw != "" {
+		score, err := strconv.Atoi(raw)
+		if err != nil || score < 0 || score > 100 {
+			return nil, fmt.Errorf("invalid min_liquidity %q: must be between 0 and 100", raw)
+		}
+		filter["liquidity.score"] = bson.M{"$gte": score}
+	}
+
+	if raw := query.Get("min_sell_through"); raw != "" {
+		rate, err := strconv.ParseFloat(raw, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("invalid min_sell_through %q: must be between 0 and 1", raw)
+		}
+		filter["liquidity.sell_through_rate"] = bson.M{"$gte": rate}
+	}
+
+	if raw := query.Get("max_days_to_sell"); raw != "" {
+		days, err := strconv.ParseFloat(raw, 64)
+		if err != nil || days <= 0 {
+			return nil, fmt.Errorf("invalid max_days_to_sell %q: must be a positive number", raw)
+		}
+		// A null estimate (nothing sold) never matches $lte
+		filter["liquidity.days_to_sell"] = bson.M{"$lte": days}
+	}
+
+	return filter, nil
+}
+
+// parseSearchSort returns the sort order for a SearchCards sort name. Sorting
+// by days_to_sell skips cards without an estimate, which would otherwise sort
+// first as nulls, by adding a condition to filter; sorting by fair_value
+// likewise skips cards without a fair value.
+func parseSearchSort(name string, filter bson.M) (bson.D, error) {
+	if name == "" {
+		name = "updated"
+	}
+	order, ok := searchSorts[name]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort %q: must be one of updated, liquidity, sell_through, days_to_sell, fair_value", name)
+	}
+	if name == "days_to_sell" {
+		if _, set := filter["liquidity.days_to_sell"]; !set {
+			filter["liquidity.days_to_sell"] = bson.M{"$type": "number"}
+		}
+	}
+	if name == "fair_value" {
+		filter["fair_value.value"] = bson.M{"$type": "number"}
+	}
+	return order, nil
+}
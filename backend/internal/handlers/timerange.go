@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// timeWindow is a resolved [Start, End] query window
+type timeWindow struct {
+	Label string
+	Start time.Time
+	End   time.Time
+}
+
+// parseTimeWindow resolves the range/from/to query parameters. Explicit RFC3339
+// from/to bounds take precedence over the named range, which defaults to fallback
+// and ends at to when only to is given.
+func parseTimeWindow(query url.Values, fallback string, now time.Time) (timeWindow, error) {
+	label := query.Get("range")
+	if label == "" {
+		label = fallback
+	}
+
+	window := timeWindow{Label: label, End: now}
+
+	// The named range ends at to when only to is given
+	if toStr := query.Get("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			return timeWindow{}, fmt.Errorf("invalid to %q: must be RFC3339", toStr)
+		}
+		window.End = to
+		window.Label = "custom"
+	}
+
+	start, err := rangeStart(label, window.End)
+	if err != nil {
+		return timeWindow{}, err
+	}
+	window.Start = start
+
+	if fromStr := query.Get("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			return timeWindow{}, fmt.Errorf("invalid from %q: must be RFC3339", fromStr)
+		}
+		window.Start = from
+		window.Label = "custom"
+	}
+
+	if !window.Start.Before(window.End) {
+		return timeWindow{}, fmt.Errorf("from must be before to")
+	}
+
+	return window, nil
+}
+
+// rangeStart returns the start of a named range ending at now
+func rangeStart(label string, now time.Time) (time.Time, error) {
+	switch label {
+	case "1d":
+		return now.AddDate(0, 0, -1), nil
+	case "7d":
+		return now.AddDate(0, 0, -7), nil
+	case "30d":
+		return now.AddDate(0, 0, -30), nil
+	case "90d":
+		return now.AddDate(0, 0, -90), nil
+	case "1y":
+		return now.AddDate(-1, 0, 0), nil
+	case "5y":
+		return now.AddDate(-5, 0, 0), nil
+	case "ytd":
+		return time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location()), nil
+	case "max":
+		return time.Time{}, nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid range %q: must be one of 1d, 7d, 30d, 90d, 1y, 5y, ytd, max", label)
+	}
+}
@@ -163,3 +163,19 @@ func (h *Handlers) verifyPassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
+
+// validSources lists the marketplaces price data can come from
+var validSources = map[string]bool{
+	"ebay":             true,
+	"tcgplayer":        true,
+	"cardmarket":       true,
+	"tcgplayer_direct": true,
+}
+
+// validateSource checks that a source filter names a known marketplace
+func validateSource(source string) error {
+	if !validSources[source] {
+		return fmt.Errorf("invalid source %q: must be one of ebay, tcgplayer, cardmarket, tcgplayer_direct", source)
+	}
+	return nil
+}
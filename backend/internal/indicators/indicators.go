@@ -0,0 +1,345 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// MaxPeriod caps lookback windows so a single request can't ask for absurd work
+const MaxPeriod = 500
+
+// Bar is a single OHLCV observation used as calculator input
+type Bar struct {
+	Timestamp time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    int
+}
+
+// Param describes a positional indicator parameter and its default value
+type Param struct {
+	Name    string
+	Default float64
+}
+
+// Line is one named output series of an indicator (e.g. Bollinger "upper")
+type Line struct {
+	Label  string
+	Points []models.IndicatorPoint
+}
+
+// Calculator computes one indicator type over a bar series
+type Calculator interface {
+	// Params returns the ordered parameters accepted by the indicator
+	Params() []Param
+	// Calculate returns one or more output lines for the given bars
+	Calculate(bars []Bar, params []float64) ([]Line, error)
+}
+
+// ohlcCalculator is implemented by calculators that need real high/low/volume
+// data rather than a close-only series
+type ohlcCalculator interface {
+	RequiresOHLC() bool
+}
+
+// RequiresOHLC reports whether any of the specs needs OHLC bars from market_data
+func RequiresOHLC(specs []Spec) bool {
+	for _, spec := range specs {
+		calc, ok := Lookup(spec.Type)
+		if !ok {
+			continue
+		}
+		if oc, ok := calc.(ohlcCalculator); ok && oc.RequiresOHLC() {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitOHLC separates the specs that can run on a close-only series from
+// those that need OHLC bars, keeping their order
+func SplitOHLC(specs []Spec) (closeOnly, ohlc []Spec) {
+	for _, spec := range specs {
+		if RequiresOHLC([]Spec{spec}) {
+			ohlc = append(ohlc, spec)
+		} else {
+			closeOnly = append(closeOnly, spec)
+		}
+	}
+	return closeOnly, ohlc
+}
+
+// Spec is a parsed indicator request such as "bollinger:20:2"
+type Spec struct {
+	Type   string
+	Params []float64
+}
+
+// Key returns the response key for the spec, e.g. "bollinger_20_2"
+func (s Spec) Key() string {
+	parts := []string{s.Type}
+	for _, p := range s.Params {
+		parts = append(parts, strconv.FormatFloat(p, 'f', -1, 64))
+	}
+	return strings.Join(parts, "_")
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Calculator)
+)
+
+// Register adds a calculator to the registry under the given type name
+func Register(name string, calc Calculator) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = calc
+}
+
+// Lookup returns the calculator registered for the given type name
+func Lookup(name string) (Calculator, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	calc, ok := registry[name]
+	return calc, ok
+}
+
+// Names returns all registered indicator type names in sorted order
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseSpecs parses a comma separated list like "sma:20,bollinger:20:2,rsi:14".
+// Missing parameters are filled with the calculator defaults.
+func ParseSpecs(raw string) ([]Spec, error) {
+	var specs []Spec
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		fields := strings.Split(item, ":")
+		values := make([]float64, 0, len(fields)-1)
+		for _, f := range fields[1:] {
+			v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid parameter %q for indicator %q", f, fields[0])
+			}
+			values = append(values, v)
+		}
+
+		spec, err := NewSpec(strings.ToLower(strings.TrimSpace(fields[0])), values)
+		if err != nil {
+			return nil, err
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// NewSpec validates the indicator type and fills in default parameters
+func NewSpec(indicatorType string, values []float64) (Spec, error) {
+	calc, ok := Lookup(indicatorType)
+	if !ok {
+		return Spec{}, fmt.Errorf("unknown indicator %q", indicatorType)
+	}
+
+	params := calc.Params()
+	if len(values) > len(params) {
+		return Spec{}, fmt.Errorf("indicator %q accepts at most %d parameters", indicatorType, len(params))
+	}
+
+	resolved := make([]float64, len(params))
+	for i, p := range params {
+		if i < len(values) {
+			resolved[i] = values[i]
+		} else {
+			resolved[i] = p.Default
+		}
+	}
+
+	return Spec{Type: indicatorType, Params: resolved}, nil
+}
+
+// Compute runs every spec against the bars and returns series keyed by spec key.
+// Multi-line indicators produce one entry per line, suffixed with the line label.
+func Compute(specs []Spec, bars []Bar) (map[string][]models.IndicatorPoint, error) {
+	result := make(map[string][]models.IndicatorPoint)
+	for _, spec := range specs {
+		calc, ok := Lookup(spec.Type)
+		if !ok {
+			return nil, fmt.Errorf("unknown indicator %q", spec.Type)
+		}
+
+		lines, err := calc.Calculate(bars, spec.Params)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", spec.Key(), err)
+		}
+
+		for _, line := range lines {
+			key := spec.Key()
+			if line.Label != "" {
+				key += "_" + line.Label
+			}
+			result[key] = line.Points
+		}
+	}
+	return result, nil
+}
+
+// BarsFromMarketData converts daily OHLC documents into calculator input
+func BarsFromMarketData(data []models.MarketData) []Bar {
+	bars := make([]Bar, len(data))
+	for i, d := range data {
+		bars[i] = Bar{
+			Timestamp: d.Date,
+			Open:      d.OpenPrice,
+			High:      d.HighPrice,
+			Low:       d.LowPrice,
+			Close:     d.ClosePrice,
+			Volume:    d.Volume,
+		}
+	}
+	return bars
+}
+
+// BarsFromPrices converts raw price points into flat bars (open = high = low = close)
+func BarsFromPrices(prices []models.PricePoint) []Bar {
+	bars := make([]Bar, len(prices))
+	for i, p := range prices {
+		bars[i] = Bar{
+			Timestamp: p.Timestamp,
+			Open:      p.Price,
+			High:      p.Price,
+			Low:       p.Price,
+			Close:     p.Price,
+			Volume:    p.Volume,
+		}
+	}
+	return bars
+}
+
+// period converts a float parameter into a validated integer lookback window
+func period(name string, value float64) (int, error) {
+	n := int(value)
+	if float64(n) != value || n < 1 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	if n > MaxPeriod {
+		return 0, fmt.Errorf("%s must not exceed %d", name, MaxPeriod)
+	}
+	return n, nil
+}
+
+// closes extracts closing prices from bars
+func closes(bars []Bar) []float64 {
+	values := make([]float64, len(bars))
+	for i, b := range bars {
+		values[i] = b.Close
+	}
+	return values
+}
+
+// volumes extracts traded volume from bars
+func volumes(bars []Bar) []float64 {
+	values := make([]float64, len(bars))
+	for i, b := range bars {
+		values[i] = float64(b.Volume)
+	}
+	return values
+}
+
+// highestHigh returns the maximum high over bars
+func highestHigh(bars []Bar) float64 {
+	high := bars[0].High
+	for _, b := range bars[1:] {
+		high = math.Max(high, b.High)
+	}
+	return high
+}
+
+// lowestLow returns the minimum low over bars
+func lowestLow(bars []Bar) float64 {
+	low := bars[0].Low
+	for _, b := range bars[1:] {
+		low = math.Min(low, b.Low)
+	}
+	return low
+}
+
+// toPoints pairs values with bar timestamps, skipping the first offset bars
+func toPoints(bars []Bar, values []float64, offset int, label string) []models.IndicatorPoint {
+	points := make([]models.IndicatorPoint, 0, len(values))
+	for i, v := range values {
+		points = append(points, models.IndicatorPoint{
+			Timestamp: bars[i+offset].Timestamp,
+			Value:     v,
+			Label:     label,
+		})
+	}
+	return points
+}
+
+// SpecFromChartIndicator builds a spec from a saved chart indicator, reading
+// parameters by name (e.g. {"period": 20, "std_dev": 2}) and defaulting the rest
+func SpecFromChartIndicator(ci models.ChartIndicator) (Spec, error) {
+	indicatorType := strings.ToLower(ci.Type)
+	calc, ok := Lookup(indicatorType)
+	if !ok {
+		return Spec{}, fmt.Errorf("unknown indicator %q", ci.Type)
+	}
+
+	values := make([]float64, 0, len(calc.Params()))
+	for _, p := range calc.Params() {
+		raw, ok := ci.Parameters[p.Name]
+		if !ok {
+			values = append(values, p.Default)
+			continue
+		}
+		v, ok := toFloat(raw)
+		if !ok {
+			return Spec{}, fmt.Errorf("invalid %s parameter for indicator %q", p.Name, ci.Type)
+		}
+		values = append(values, v)
+	}
+
+	return NewSpec(indicatorType, values)
+}
+
+// toFloat converts JSON and BSON numeric values to float64
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,226 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+func init() {
+	Register("rsi", rsiCalculator{})
+	Register("macd", macdCalculator{})
+	Register("stochastic", stochasticCalculator{})
+	Register("williams_r", williamsRCalculator{})
+	Register("cci", cciCalculator{})
+}
+
+// rsiCalculator computes the Relative Strength Index using Wilder smoothing
+type rsiCalculator struct{}
+
+func (rsiCalculator) Params() []Param {
+	return []Param{{Name: "period", Default: 14}}
+}
+
+func (rsiCalculator) Calculate(bars []Bar, params []float64) ([]Line, error) {
+	n, err := period("period", params[0])
+	if err != nil {
+		return nil, err
+	}
+
+	values := closes(bars)
+	if len(values) <= n {
+		return []Line{{Points: toPoints(bars, nil, 0, "")}}, nil
+	}
+
+	// Seed average gain/loss with the first n changes
+	avgGain, avgLoss := 0.0, 0.0
+	for i := 1; i <= n; i++ {
+		change := values[i] - values[i-1]
+		if change > 0 {
+			avgGain += change
+		} else {
+			avgLoss -= change
+		}
+	}
+	avgGain /= float64(n)
+	avgLoss /= float64(n)
+
+	result := []float64{rsiValue(avgGain, avgLoss)}
+	for i := n + 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		gain, loss := 0.0, 0.0
+		if change > 0 {
+			gain = change
+		} else {
+			loss = -change
+		}
+		avgGain = (avgGain*float64(n-1) + gain) / float64(n)
+		avgLoss = (avgLoss*float64(n-1) + loss) / float64(n)
+		result = append(result, rsiValue(avgGain, avgLoss))
+	}
+
+	return []Line{{Points: toPoints(bars, result, n, "")}}, nil
+}
+
+// rsiValue converts average gain/loss into the 0-100 RSI scale
+func rsiValue(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	rs := avgGain / avgLoss
+	return 100 - 100/(1+rs)
+}
+
+// macdCalculator computes MACD (fast EMA - slow EMA), its signal line and histogram
+type macdCalculator struct{}
+
+func (macdCalculator) Params() []Param {
+	return []Param{{Name: "fast", Default: 12}, {Name: "slow", Default: 26}, {Name: "signal", Default: 9}}
+}
+
+func (macdCalculator) Calculate(bars []Bar, params []float64) ([]Line, error) {
+	fast, err := period("fast", params[0])
+	if err != nil {
+		return nil, err
+	}
+	slow, err := period("slow", params[1])
+	if err != nil {
+		return nil, err
+	}
+	signalPeriod, err := period("signal", params[2])
+	if err != nil {
+		return nil, err
+	}
+	if fast >= slow {
+		return nil, fmt.Errorf("fast period must be shorter than slow period")
+	}
+
+	values := closes(bars)
+	slowEMA := ema(values, slow)
+	fastEMA := ema(values, fast)
+
+	// Align the fast EMA with the slow EMA, which starts slow-fast bars later
+	macd := make([]float64, len(slowEMA))
+	for i := range slowEMA {
+		macd[i] = fastEMA[i+slow-fast] - slowEMA[i]
+	}
+
+	signal := ema(macd, signalPeriod)
+	histogram := make([]float64, len(signal))
+	for i := range signal {
+		histogram[i] = macd[i+signalPeriod-1] - signal[i]
+	}
+
+	signalOffset := slow - 1 + signalPeriod - 1
+	return []Line{
+		{Label: "macd", Points: toPoints(bars, macd, slow-1, "macd")},
+		{Label: "signal", Points: toPoints(bars, signal, signalOffset, "signal")},
+		{Label: "histogram", Points: toPoints(bars, histogram, signalOffset, "histogram")},
+	}, nil
+}
+
+// stochasticCalculator computes the stochastic oscillator %K and its %D average
+type stochasticCalculator struct{}
+
+func (stochasticCalculator) Params() []Param {
+	return []Param{{Name: "k_period", Default: 14}, {Name: "d_period", Default: 3}}
+}
+
+func (stochasticCalculator) RequiresOHLC() bool { return true }
+
+func (stochasticCalculator) Calculate(bars []Bar, params []float64) ([]Line, error) {
+	kPeriod, err := period("k_period", params[0])
+	if err != nil {
+		return nil, err
+	}
+	dPeriod, err := period("d_period", params[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var k []float64
+	for i := kPeriod - 1; i < len(bars); i++ {
+		window := bars[i-kPeriod+1 : i+1]
+		high, low := highestHigh(window), lowestLow(window)
+		value := 50.0
+		if high > low {
+			value = (bars[i].Close - low) / (high - low) * 100
+		}
+		k = append(k, value)
+	}
+	d := sma(k, dPeriod)
+
+	return []Line{
+		{Label: "k", Points: toPoints(bars, k, kPeriod-1, "k")},
+		{Label: "d", Points: toPoints(bars, d, kPeriod-1+dPeriod-1, "d")},
+	}, nil
+}
+
+// williamsRCalculator computes Williams %R on a -100..0 scale
+type williamsRCalculator struct{}
+
+func (williamsRCalculator) Params() []Param {
+	return []Param{{Name: "period", Default: 14}}
+}
+
+func (williamsRCalculator) RequiresOHLC() bool { return true }
+
+func (williamsRCalculator) Calculate(bars []Bar, params []float64) ([]Line, error) {
+	n, err := period("period", params[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var values []float64
+	for i := n - 1; i < len(bars); i++ {
+		window := bars[i-n+1 : i+1]
+		high, low := highestHigh(window), lowestLow(window)
+		value := -50.0
+		if high > low {
+			value = (high - bars[i].Close) / (high - low) * -100
+		}
+		values = append(values, value)
+	}
+
+	return []Line{{Points: toPoints(bars, values, n-1, "")}}, nil
+}
+
+// cciCalculator computes the Commodity Channel Index from typical prices
+type cciCalculator struct{}
+
+func (cciCalculator) Params() []Param {
+	return []Param{{Name: "period", Default: 20}}
+}
+
+func (cciCalculator) RequiresOHLC() bool { return true }
+
+func (cciCalculator) Calculate(bars []Bar, params []float64) ([]Line, error) {
+	n, err := period("period", params[0])
+	if err != nil {
+		return nil, err
+	}
+
+	typical := make([]float64, len(bars))
+	for i, b := range bars {
+		typical[i] = (b.High + b.Low + b.Close) / 3
+	}
+
+	means := sma(typical, n)
+	values := make([]float64, len(means))
+	for i, mean := range means {
+		// Mean absolute deviation over the same window
+		deviation := 0.0
+		for _, tp := range typical[i : i+n] {
+			deviation += math.Abs(tp - mean)
+		}
+		deviation /= float64(n)
+
+		if deviation != 0 {
+			values[i] = (typical[i+n-1] - mean) / (0.015 * deviation)
+		}
+	}
+
+	return []Line{{Points: toPoints(bars, values, n-1, "")}}, nil
+}
@@ -0,0 +1,133 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+func init() {
+	Register("sma", smaCalculator{})
+	Register("ema", emaCalculator{})
+	Register("bollinger", bollingerCalculator{})
+}
+
+// smaCalculator computes the simple moving average of closing prices
+type smaCalculator struct{}
+
+func (smaCalculator) Params() []Param {
+	return []Param{{Name: "period", Default: 20}}
+}
+
+func (smaCalculator) Calculate(bars []Bar, params []float64) ([]Line, error) {
+	n, err := period("period", params[0])
+	if err != nil {
+		return nil, err
+	}
+	values := sma(closes(bars), n)
+	return []Line{{Points: toPoints(bars, values, n-1, "")}}, nil
+}
+
+// emaCalculator computes the exponential moving average of closing prices
+type emaCalculator struct{}
+
+func (emaCalculator) Params() []Param {
+	return []Param{{Name: "period", Default: 20}}
+}
+
+func (emaCalculator) Calculate(bars []Bar, params []float64) ([]Line, error) {
+	n, err := period("period", params[0])
+	if err != nil {
+		return nil, err
+	}
+	values := ema(closes(bars), n)
+	return []Line{{Points: toPoints(bars, values, n-1, "")}}, nil
+}
+
+// bollingerCalculator computes Bollinger Bands (SMA +/- k standard deviations)
+type bollingerCalculator struct{}
+
+func (bollingerCalculator) Params() []Param {
+	return []Param{{Name: "period", Default: 20}, {Name: "std_dev", Default: 2}}
+}
+
+func (bollingerCalculator) Calculate(bars []Bar, params []float64) ([]Line, error) {
+	n, err := period("period", params[0])
+	if err != nil {
+		return nil, err
+	}
+	k := params[1]
+	if k <= 0 || k > 10 {
+		return nil, fmt.Errorf("std_dev must be between 0 and 10")
+	}
+
+	values := closes(bars)
+	middle := sma(values, n)
+	upper := make([]float64, len(middle))
+	lower := make([]float64, len(middle))
+	for i, mean := range middle {
+		sd := stdDev(values[i:i+n], mean)
+		upper[i] = mean + k*sd
+		lower[i] = mean - k*sd
+	}
+
+	return []Line{
+		{Label: "upper", Points: toPoints(bars, upper, n-1, "upper")},
+		{Label: "middle", Points: toPoints(bars, middle, n-1, "middle")},
+		{Label: "lower", Points: toPoints(bars, lower, n-1, "lower")},
+	}, nil
+}
+
+// sma returns the rolling mean of values; result[i] covers values[i : i+n]
+func sma(values []float64, n int) []float64 {
+	if len(values) < n {
+		return nil
+	}
+	result := make([]float64, 0, len(values)-n+1)
+	sum := 0.0
+	for i, v := range values {
+		sum += v
+		if i >= n {
+			sum -= values[i-n]
+		}
+		if i >= n-1 {
+			result = append(result, sum/float64(n))
+		}
+	}
+	return result
+}
+
+// ema returns the exponential moving average seeded with the first SMA;
+// result[i] corresponds to values[i+n-1]
+func ema(values []float64, n int) []float64 {
+	if len(values) < n {
+		return nil
+	}
+	alpha := 2.0 / float64(n+1)
+	result := make([]float64, 0, len(values)-n+1)
+
+	seed := 0.0
+	for _, v := range values[:n] {
+		seed += v
+	}
+	prev := seed / float64(n)
+	result = append(result, prev)
+
+	for _, v := range values[n:] {
+		prev = alpha*v + (1-alpha)*prev
+		result = append(result, prev)
+	}
+	return result
+}
+
+// stdDev returns the population standard deviation of values around mean
+func stdDev(values []float64, mean float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		d := v - mean
+		sum += d * d
+	}
+	return math.Sqrt(sum / float64(len(values)))
+}
@@ -0,0 +1,68 @@
+package indicators
+
+import "math"
+
+func init() {
+	Register("atr", atrCalculator{})
+	Register("volume_sma", volumeSMACalculator{})
+}
+
+// atrCalculator computes the Average True Range using Wilder smoothing
+type atrCalculator struct{}
+
+func (atrCalculator) Params() []Param {
+	return []Param{{Name: "period", Default: 14}}
+}
+
+func (atrCalculator) RequiresOHLC() bool { return true }
+
+func (atrCalculator) Calculate(bars []Bar, params []float64) ([]Line, error) {
+	n, err := period("period", params[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) <= n {
+		return []Line{{Points: toPoints(bars, nil, 0, "")}}, nil
+	}
+
+	// True range needs the previous close, so it starts at the second bar
+	trueRanges := make([]float64, 0, len(bars)-1)
+	for i := 1; i < len(bars); i++ {
+		prevClose := bars[i-1].Close
+		tr := math.Max(bars[i].High-bars[i].Low,
+			math.Max(math.Abs(bars[i].High-prevClose), math.Abs(bars[i].Low-prevClose)))
+		trueRanges = append(trueRanges, tr)
+	}
+
+	atr := 0.0
+	for _, tr := range trueRanges[:n] {
+		atr += tr
+	}
+	atr /= float64(n)
+
+	values := []float64{atr}
+	for _, tr := range trueRanges[n:] {
+		atr = (atr*float64(n-1) + tr) / float64(n)
+		values = append(values, atr)
+	}
+
+	return []Line{{Points: toPoints(bars, values, n, "")}}, nil
+}
+
+// volumeSMACalculator computes the simple moving average of traded volume
+type volumeSMACalculator struct{}
+
+func (volumeSMACalculator) Params() []Param {
+	return []Param{{Name: "period", Default: 20}}
+}
+
+func (volumeSMACalculator) RequiresOHLC() bool { return true }
+
+func (volumeSMACalculator) Calculate(bars []Bar, params []float64) ([]Line, error) {
+	n, err := period("period", params[0])
+	if err != nil {
+		return nil, err
+	}
+	values := sma(volumes(bars), n)
+	return []Line{{Points: toPoints(bars, values, n-1, "")}}, nil
+}
@@ -0,0 +1,121 @@
+package indices
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/jamesc159/monmetrics/internal/aggregator"
+	"github.com/jamesc159/monmetrics/internal/analytics"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// Weighting schemes
+const (
+	WeightingPrice = "price"
+	WeightingEqual = "equal"
+)
+
+// rebalanceBucket maps a rebalance frequency to the period a date falls in
+func rebalanceBucket(rebalance string) func(time.Time) time.Time {
+	switch rebalance {
+	case "daily":
+		return aggregator.TruncateDay
+	case "weekly":
+		return aggregator.TruncateWeek
+	default:
+		return aggregator.TruncateMonth
+	}
+}
+
+// Compute builds the daily index series from constituent closes. Holdings are
+// reset whenever a new rebalance period starts: price weighting holds equal
+// units of every card (a Dow-style price average) while equal weighting holds
+// equal dollar amounts. Between rebalances holdings drift with prices, and
+// cards without a fresh close carry their last known price.
+func Compute(index models.MarketIndex, closes map[primitive.ObjectID][]analytics.Point) []models.IndexValue {
+	byDate := make(map[time.Time]map[primitive.ObjectID]float64)
+	for cardID, points := range closes {
+		for _, p := range points {
+			if byDate[p.Date] == nil {
+				byDate[p.Date] = make(map[primitive.ObjectID]float64)
+			}
+			byDate[p.Date][cardID] = p.Value
+		}
+	}
+
+	dates := make([]time.Time, 0, len(byDate))
+	for d := range byDate {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	base := index.BaseValue
+	if base <= 0 {
+		base = 1000
+	}
+	bucket := rebalanceBucket(index.Rebalance)
+
+	values := make([]models.IndexValue, 0, len(dates))
+	lastPrice := make(map[primitive.ObjectID]float64)
+	units := make(map[primitive.ObjectID]float64)
+	value := base
+
+	for i, date := range dates {
+		for cardID, price := range byDate[date] {
+			lastPrice[cardID] = price
+		}
+
+		if i > 0 {
+			value = 0
+			for cardID, u := range units {
+				value += u * lastPrice[cardID]
+			}
+		}
+
+		if i == 0 || !bucket(date).Equal(bucket(dates[i-1])) {
+			units = rebalance(index.Weighting, value, lastPrice)
+		}
+
+		values = append(values, models.IndexValue{
+			IndexID:      index.ID,
+			Date:         date,
+			Value:        math.Round(value*100) / 100,
+			Constituents: len(byDate[date]),
+		})
+	}
+
+	return values
+}
+
+// rebalance allocates value across every priced card per the weighting scheme
+func rebalance(weighting string, value float64, prices map[primitive.ObjectID]float64) map[primitive.ObjectID]float64 {
+	units := make(map[primitive.ObjectID]float64, len(prices))
+	if len(prices) == 0 {
+		return units
+	}
+
+	if weighting == WeightingEqual {
+		allocation := value / float64(len(prices))
+		for cardID, price := range prices {
+			if price > 0 {
+				units[cardID] = allocation / price
+			}
+		}
+		return units
+	}
+
+	sum := 0.0
+	for _, price := range prices {
+		sum += price
+	}
+	if sum == 0 {
+		return units
+	}
+	for cardID := range prices {
+		units[cardID] = value / sum
+	}
+	return units
+}
@@ -0,0 +1,247 @@
+package indices
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/analytics"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// Builder maintains index definitions and recomputes their daily values
+type Builder struct {
+	db        *mongo.Database
+	weighting string
+	rebalance string
+}
+
+// Result summarizes an index computation run
+type Result struct {
+	Indices int `json:"indices"`
+	Values  int `json:"values"`
+}
+
+// New creates a Builder. weighting and rebalance are the defaults applied to
+// newly discovered indices; existing definitions keep their stored settings.
+func New(db *mongo.Database, weighting, rebalance string) *Builder {
+	return &Builder{db: db, weighting: weighting, rebalance: rebalance}
+}
+
+// Run syncs index definitions with the catalog and recomputes every index
+func (b *Builder) Run(ctx context.Context) (Result, error) {
+	var result Result
+
+	if err := b.Sync(ctx); err != nil {
+		return result, err
+	}
+
+	cursor, err := b.db.Collection("indices").Find(ctx, bson.M{})
+	if err != nil {
+		return result, fmt.Errorf("failed to list indices: %v", err)
+	}
+	var indexList []models.MarketIndex
+	if err := cursor.All(ctx, &indexList); err != nil {
+		return result, fmt.Errorf("failed to decode indices: %v", err)
+	}
+
+	for _, index := range indexList {
+		n, err := b.ComputeIndex(ctx, index)
+		if err != nil {
+			return result, err
+		}
+		result.Indices++
+		result.Values += n
+	}
+
+	return result, nil
+}
+
+// Sync ensures a singles and sealed index exists for every game in the
+// catalog, and for every set within each game
+func (b *Builder) Sync(ctx context.Context) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": bson.M{"game": "$game", "set": "$set", "category": "$category"}}}},
+	}
+	cursor, err := b.db.Collection("cards").Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to list game categories: %v", err)
+	}
+
+	var groups []struct {
+		ID struct {
+			Game     string `bson:"game"`
+			Set      string `bson:"set"`
+			Category string `bson:"category"`
+		} `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return fmt.Errorf("failed to decode game categories: %v", err)
+	}
+
+	now := time.Now().UTC()
+	for _, g := range groups {
+		if g.ID.Game == "" || g.ID.Category == "" {
+			continue
+		}
+
+		label := "Singles"
+		if g.ID.Category == "sealed" {
+			label = "Sealed"
+		}
+
+		// The game-wide index is revisited for each set; the upsert only inserts it once
+		if err := b.ensure(ctx, g.ID.Game, "", g.ID.Category, g.ID.Game+" "+label, now); err != nil {
+			return err
+		}
+		if g.ID.Set != "" {
+			if err := b.ensure(ctx, g.ID.Game, g.ID.Set, g.ID.Category, g.ID.Game+" "+g.ID.Set+" "+label, now); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensure inserts an index definition for the slice of the catalog unless one
+// already exists under the same slug
+func (b *Builder) ensure(ctx context.Context, game, set, category, title string, now time.Time) error {
+	slug := Slugify(title)
+	_, err := b.db.Collection("indices").UpdateOne(ctx,
+		bson.M{"slug": slug},
+		bson.M{"$setOnInsert": models.MarketIndex{
+			Slug:         slug,
+			Name:         title + " Index",
+			Game:         game,
+			Set:          set,
+			Category:     category,
+			Weighting:    b.weighting,
+			Rebalance:    b.rebalance,
+			BaseValue:    1000,
+			Constituents: []primitive.ObjectID{},
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to upsert index %s: %v", slug, err)
+	}
+	return nil
+}
+
+// ComputeIndex recomputes the full history of a single index from market_data
+func (b *Builder) ComputeIndex(ctx context.Context, index models.MarketIndex) (int, error) {
+	filter := bson.M{}
+	if index.Game != "" {
+		filter["game"] = index.Game
+	}
+	if index.Set != "" {
+		filter["set"] = index.Set
+	}
+	if index.Category != "" {
+		filter["category"] = index.Category
+	}
+
+	cardIDs, err := b.db.Collection("cards").Distinct(ctx, "_id", filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list constituents for %s: %v", index.Slug, err)
+	}
+
+	constituents := make([]primitive.ObjectID, 0, len(cardIDs))
+	for _, v := range cardIDs {
+		if id, ok := v.(primitive.ObjectID); ok {
+			constituents = append(constituents, id)
+		}
+	}
+
+	cursor, err := b.db.Collection("market_data").Find(ctx,
+		bson.M{"card_id": bson.M{"$in": constituents}},
+		options.Find().SetSort(bson.M{"date": 1}),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load market data for %s: %v", index.Slug, err)
+	}
+	var marketData []models.MarketData
+	if err := cursor.All(ctx, &marketData); err != nil {
+		return 0, fmt.Errorf("failed to decode market data for %s: %v", index.Slug, err)
+	}
+
+	byCard := make(map[primitive.ObjectID][]models.MarketData)
+	for _, d := range marketData {
+		byCard[d.CardID] = append(byCard[d.CardID], d)
+	}
+	closes := make(map[primitive.ObjectID][]analytics.Point, len(byCard))
+	for cardID, data := range byCard {
+		closes[cardID] = analytics.ClosesFromMarketData(data)
+	}
+
+	values := Compute(index, closes)
+	if len(values) > 0 {
+		writes := make([]mongo.WriteModel, 0, len(values))
+		for _, v := range values {
+			writes = append(writes, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"index_id": v.IndexID, "date": v.Date}).
+				SetReplacement(v).
+				SetUpsert(true))
+		}
+		if _, err := b.db.Collection("index_values").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return 0, fmt.Errorf("failed to write values for %s: %v", index.Slug, err)
+		}
+	}
+
+	// The history is rebuilt in full, so days it no longer covers (e.g. after
+	// constituents left or their candles were removed) must not keep old values
+	dates := make(bson.A, 0, len(values))
+	for _, v := range values {
+		dates = append(dates, v.Date)
+	}
+	_, err = b.db.Collection("index_values").DeleteMany(ctx, bson.M{
+		"index_id": index.ID,
+		"date":     bson.M{"$nin": dates},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove stale values for %s: %v", index.Slug, err)
+	}
+
+	update := bson.M{
+		"constituents":     constituents,
+		"last_computed_at": time.Now().UTC(),
+		"updated_at":       time.Now().UTC(),
+	}
+	if n := len(values); n > 0 {
+		update["current_value"] = values[n-1].Value
+		if n > 1 && values[n-2].Value > 0 {
+			update["change_pct"] = (values[n-1].Value - values[n-2].Value) / values[n-2].Value * 100
+		}
+	}
+	if _, err := b.db.Collection("indices").UpdateOne(ctx, bson.M{"_id": index.ID}, bson.M{"$set": update}); err != nil {
+		return 0, fmt.Errorf("failed to update index %s: %v", index.Slug, err)
+	}
+
+	return len(values), nil
+}
+
+// Slugify converts a name like "Pokemon Singles" into "pokemon-singles"
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
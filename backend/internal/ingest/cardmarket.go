@@ -0,0 +1,129 @@
+package ingest
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/conditions"
+)
+
+// cardmarketPageSize is the page size Cardmarket's API allows at most
+const cardmarketPageSize = 100
+
+// cardMarket reads articles (asks) and products from Cardmarket's API. The
+// API doesn't publish other users' sales, so there is no sales feed.
+type cardMarket struct {
+	client client
+}
+
+// NewCardmarket creates a Cardmarket source
+func NewCardmarket(endpoint Endpoint) PriceSource {
+	return &cardMarket{client: newClient(endpoint)}
+}
+
+func (c *cardMarket) Name() string { return "cardmarket" }
+
+func (c *cardMarket) Sales(ctx context.Context, cursor string, since time.Time) (Page[Sale], error) {
+	return Page[Sale]{}, ErrNotSupported
+}
+
+// cardmarketConditions maps Cardmarket's grading scale to conditions
+var cardmarketConditions = map[string]string{
+	"MT": conditions.NearMint,
+	"NM": conditions.NearMint,
+	"EX": conditions.LightlyPlayed,
+	"GD": conditions.ModeratelyPlayed,
+	"LP": conditions.HeavilyPlayed,
+	"PL": conditions.HeavilyPlayed,
+	"PO": conditions.Damaged,
+}
+
+type cardmarketArticlesResponse struct {
+	Article []struct {
+		IDArticle int `json:"idArticle"`
+		IDProduct int `json:"idProduct"`
+		Language  struct {
+			LanguageName string `json:"languageName"`
+		} `json:"language"`
+		Price     float64 `json:"price"`
+		Count     int     `json:"count"`
+		Condition string  `json:"condition"`
+		IsFoil    bool    `json:"isFoil"`
+		IsFirstEd bool    `json:"isFirstEd"`
+		Seller    struct {
+			Username string `json:"username"`
+		} `json:"seller"`
+		LastEdited time.Time `json:"lastEdited"`
+	} `json:"article"`
+}
+
+func (c *cardMarket) Listings(ctx context.Context, cursor string) (Page[Listing], error) {
+	start, _ := strconv.Atoi(cursor)
+	query := url.Values{}
+	query.Set("maxResults", strconv.Itoa(cardmarketPageSize))
+	if start > 0 {
+		query.Set("start", strconv.Itoa(start))
+	}
+
+	var resp cardmarketArticlesResponse
+	if err := c.client.get(ctx, "/ws/v2.0/output.json/articles", query, &resp); err != nil {
+		return Page[Listing]{}, err
+	}
+
+	// A full page means there may be more
+	page := Page[Listing]{}
+	if len(resp.Article) == cardmarketPageSize {
+		page.Next = strconv.Itoa(start + cardmarketPageSize)
+	}
+	for _, a := range resp.Article {
+		condition, ok := cardmarketConditions[a.Condition]
+		if !ok || a.IDProduct == 0 {
+			continue
+		}
+		printing := Printing{Finish: "normal", Language: languageOf(a.Language.LanguageName)}
+		if a.IsFoil {
+			printing.Finish = "foil"
+		}
+		if a.IsFirstEd {
+			printing.Edition = "1st_edition"
+		}
+		page.Items = append(page.Items, Listing{
+			ID:        strconv.Itoa(a.IDArticle),
+			ProductID: strconv.Itoa(a.IDProduct),
+			Printing:  printing,
+			Price:     a.Price,
+			Currency:  "EUR",
+			Quantity:  a.Count,
+			Condition: condition,
+			Seller:    a.Seller.Username,
+			ListedAt:  a.LastEdited,
+		})
+	}
+	return page, nil
+}
+
+type cardmarketProductResponse struct {
+	Product struct {
+		IDProduct     int    `json:"idProduct"`
+		EnName        string `json:"enName"`
+		ExpansionName string `json:"expansionName"`
+		Number        string `json:"number"`
+		GameName      string `json:"gameName"`
+	} `json:"product"`
+}
+
+func (c *cardMarket) Product(ctx context.Context, externalID string) (Product, error) {
+	var resp cardmarketProductResponse
+	if err := c.client.get(ctx, "/ws/v2.0/output.json/products/"+url.PathEscape(externalID), nil, &resp); err != nil {
+		return Product{}, err
+	}
+	return Product{
+		ID:     strconv.Itoa(resp.Product.IDProduct),
+		Name:   resp.Product.EnName,
+		Set:    resp.Product.ExpansionName,
+		Number: resp.Product.Number,
+		Game:   gameName(resp.Product.GameName),
+	}, nil
+}
@@ -0,0 +1,66 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/conditions"
+)
+
+// openCardmarket reads Cardmarket's fixtures through FixtureTransport
+func openCardmarket(t *testing.T) PriceSource {
+	t.Helper()
+	source, err := Open("cardmarket", Endpoint{}, fixturesDir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return source
+}
+
+func TestCardmarketSalesNotSupported(t *testing.T) {
+	_, err := openCardmarket(t).Sales(context.Background(), "", time.Now())
+	if !errors.Is(err, ErrNotSupported) {
+		t.Errorf("Sales error = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestCardmarketListings(t *testing.T) {
+	page, err := openCardmarket(t).Listings(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Listings: %v", err)
+	}
+	// A short page is the last one
+	if page.Next != "" || len(page.Items) != 4 {
+		t.Fatalf("got %d listings, next %q; want 4 and no next", len(page.Items), page.Next)
+	}
+
+	german := page.Items[1]
+	if german.ID != "1180000002" || german.ProductID != "587001" || german.Price != 949 || german.Currency != "EUR" {
+		t.Errorf("unexpected listing: %+v", german)
+	}
+	if german.Printing != (Printing{Finish: "normal", Language: "de"}) {
+		t.Errorf("Printing = %+v, want German normal", german.Printing)
+	}
+	if got := page.Items[2].Condition; got != conditions.LightlyPlayed {
+		t.Errorf("EX condition = %q, want Lightly Played", got)
+	}
+}
+
+func TestCardmarketProduct(t *testing.T) {
+	product, err := openCardmarket(t).Product(context.Background(), "701122")
+	if err != nil {
+		t.Fatalf("Product: %v", err)
+	}
+	want := Product{
+		ID:     "701122",
+		Name:   "The One Ring",
+		Set:    "The Lord of the Rings: Tales of Middle-earth",
+		Number: "246",
+		Game:   "Magic The Gathering",
+	}
+	if product != want {
+		t.Errorf("Product = %+v, want %+v", product, want)
+	}
+}
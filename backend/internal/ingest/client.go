@@ -0,0 +1,97 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Endpoint is where a source's API is reached: the marketplace itself, a
+// local stand-in server, or recorded fixtures through FixtureTransport
+type Endpoint struct {
+	BaseURL string
+	Token   string       // Sent as a bearer token when set
+	Client  *http.Client // Defaults to a client with a 30s timeout
+}
+
+// StatusError is a non-2xx API response
+type StatusError struct {
+	URL        string
+	StatusCode int
+	RetryAfter time.Duration // From the Retry-After header, when sent
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("GET %s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Temporary reports whether the request may succeed if retried: rate limits
+// and server errors are, other client errors are not
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// retryable reports whether a failed request is worth retrying: network
+// failures, rate limits and server errors are; bad requests and responses
+// that don't decode are not
+func retryable(err error) bool {
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.Temporary()
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// client fetches JSON from a source's API
+type client struct {
+	endpoint Endpoint
+}
+
+func newClient(endpoint Endpoint) client {
+	if endpoint.Client == nil {
+		endpoint.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	endpoint.BaseURL = strings.TrimRight(endpoint.BaseURL, "/")
+	return client{endpoint: endpoint}
+}
+
+// get requests path with the query parameters and decodes the JSON response into out
+func (c client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	u := c.endpoint.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.endpoint.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.endpoint.Token)
+	}
+
+	resp, err := c.endpoint.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		io.Copy(io.Discard, resp.Body)
+		statusErr := &StatusError{URL: u, StatusCode: resp.StatusCode}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			statusErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return statusErr
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,244 @@
+package ingest
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/grading"
+)
+
+// ebayCategory is eBay's "CCG Individual Cards" category
+const ebayCategory = "183454"
+
+// ebayPageSize is the page size eBay's search APIs allow at most
+const ebayPageSize = 200
+
+// eBay reads sold items from the Marketplace Insights API, live asks from the
+// Browse API and products from the Catalog API, keyed by ePID
+type eBay struct {
+	client client
+}
+
+// NewEBay creates an eBay source
+func NewEBay(endpoint Endpoint) PriceSource {
+	return &eBay{client: newClient(endpoint)}
+}
+
+func (e *eBay) Name() string { return "ebay" }
+
+type ebayAmount struct {
+	Value    string `json:"value"`
+	Currency string `json:"currency"`
+}
+
+type ebayDescriptor struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type ebaySalesResponse struct {
+	Next      string `json:"next"`
+	ItemSales []struct {
+		ItemID               string           `json:"itemId"`
+		Title                string           `json:"title"`
+		EPID                 string           `json:"epid"`
+		LastSoldDate         time.Time        `json:"lastSoldDate"`
+		LastSoldPrice        ebayAmount       `json:"lastSoldPrice"`
+		TotalSoldQuantity    int              `json:"totalSoldQuantity"`
+		ConditionDescriptors []ebayDescriptor `json:"conditionDescriptors"`
+	} `json:"itemSales"`
+}
+
+func (e *eBay) Sales(ctx context.Context, cursor string, since time.Time) (Page[Sale], error) {
+	query := ebayQuery(cursor)
+	query.Set("filter", "lastSoldDate:["+since.UTC().Format(time.RFC3339)+"..]")
+
+	var resp ebaySalesResponse
+	if err := e.client.get(ctx, "/buy/marketplace_insights/v1_beta/item_sales/search", query, &resp); err != nil {
+		return Page[Sale]{}, err
+	}
+
+	page := Page[Sale]{Next: ebayNext(resp.Next)}
+	for _, item := range resp.ItemSales {
+		price, err := strconv.ParseFloat(item.LastSoldPrice.Value, 64)
+		if err != nil || item.EPID == "" {
+			continue
+		}
+		grader, grade, ok := ebayGrade(item.ConditionDescriptors, item.Title)
+		if !ok {
+			continue
+		}
+		page.Items = append(page.Items, Sale{
+			// Multi-quantity listings sell repeatedly under one item ID
+			ID:        item.ItemID + "@" + item.LastSoldDate.UTC().Format(time.RFC3339),
+			ProductID: item.EPID,
+			Price:     price,
+			Currency:  item.LastSoldPrice.Currency,
+			Quantity:  item.TotalSoldQuantity,
+			Condition: conditionOf(ebayDescriptorValue(item.ConditionDescriptors, "Card Condition")),
+			Grader:    grader,
+			Grade:     grade,
+			SoldAt:    item.LastSoldDate,
+		})
+	}
+	return page, nil
+}
+
+type ebayListingsResponse struct {
+	Next          string `json:"next"`
+	ItemSummaries []struct {
+		ItemID               string           `json:"itemId"`
+		Title                string           `json:"title"`
+		EPID                 string           `json:"epid"`
+		Price                ebayAmount       `json:"price"`
+		ConditionDescriptors []ebayDescriptor `json:"conditionDescriptors"`
+		Seller               struct {
+			Username string `json:"username"`
+		} `json:"seller"`
+		Image struct {
+			ImageURL string `json:"imageUrl"`
+		} `json:"image"`
+		ItemCreationDate time.Time `json:"itemCreationDate"`
+	} `json:"itemSummaries"`
+}
+
+func (e *eBay) Listings(ctx context.Context, cursor string) (Page[Listing], error) {
+	var resp ebayListingsResponse
+	if err := e.client.get(ctx, "/buy/browse/v1/item_summary/search", ebayQuery(cursor), &resp); err != nil {
+		return Page[Listing]{}, err
+	}
+
+	page := Page[Listing]{Next: ebayNext(resp.Next)}
+	for _, item := range resp.ItemSummaries {
+		price, err := strconv.ParseFloat(item.Price.Value, 64)
+		if err != nil || item.EPID == "" {
+			continue
+		}
+		grader, grade, ok := ebayGrade(item.ConditionDescriptors, item.Title)
+		if !ok {
+			continue
+		}
+		page.Items = append(page.Items, Listing{
+			ID:        item.ItemID,
+			ProductID: item.EPID,
+			Title:     item.Title,
+			Price:     price,
+			Currency:  item.Price.Currency,
+			Quantity:  1,
+			Condition: conditionOf(ebayDescriptorValue(item.ConditionDescriptors, "Card Condition")),
+			Seller:    item.Seller.Username,
+			Grader:    grader,
+			Grade:     grade,
+			ImageURL:  item.Image.ImageURL,
+			ListedAt:  item.ItemCreationDate,
+		})
+	}
+	return page, nil
+}
+
+type ebayProductResponse struct {
+	EPID    string `json:"epid"`
+	Title   string `json:"title"`
+	Aspects []struct {
+		LocalizedName   string   `json:"localizedName"`
+		LocalizedValues []string `json:"localizedValues"`
+	} `json:"aspects"`
+}
+
+func (e *eBay) Product(ctx context.Context, externalID string) (Product, error) {
+	var resp ebayProductResponse
+	if err := e.client.get(ctx, "/commerce/catalog/v1_beta/product/"+url.PathEscape(externalID), nil, &resp); err != nil {
+		return Product{}, err
+	}
+
+	aspects := make(map[string]string)
+	for _, a := range resp.Aspects {
+		if len(a.LocalizedValues) > 0 {
+			aspects[a.LocalizedName] = a.LocalizedValues[0]
+		}
+	}
+	name := aspects["Card Name"]
+	if name == "" {
+		name = resp.Title
+	}
+	return Product{
+		ID:     resp.EPID,
+		Name:   name,
+		Set:    aspects["Set"],
+		Number: aspects["Card Number"],
+		Game:   gameName(aspects["Game"]),
+		Printing: Printing{
+			Finish:   finishOf(aspects["Finish"]),
+			Language: languageOf(aspects["Language"]),
+		},
+	}, nil
+}
+
+// ebayQuery returns the search parameters for the page at an offset cursor
+func ebayQuery(cursor string) url.Values {
+	query := url.Values{}
+	query.Set("category_ids", ebayCategory)
+	query.Set("limit", strconv.Itoa(ebayPageSize))
+	if cursor != "" {
+		query.Set("offset", cursor)
+	}
+	return query
+}
+
+// ebayNext returns the offset of the page eBay's "next" link points to
+func ebayNext(next string) string {
+	if next == "" {
+		return ""
+	}
+	u, err := url.Parse(next)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("offset")
+}
+
+// ebayDescriptorValue returns a condition descriptor's first value
+func ebayDescriptorValue(descriptors []ebayDescriptor, name string) string {
+	for _, d := range descriptors {
+		if strings.EqualFold(d.Name, name) && len(d.Values) > 0 {
+			return d.Values[0]
+		}
+	}
+	return ""
+}
+
+// ebayGradeTitle finds a grade in a listing title such as "PSA 10 Gem Mint"
+var ebayGradeTitle = regexp.MustCompile(`(?i)\b(PSA|BGS|CGC)\s*(10|[1-9](?:\.5)?)\b`)
+
+// ebayGrade returns a graded copy's grader and grade from its condition
+// descriptors, falling back to the title; raw copies return no grader.
+// Copies graded by unsupported companies report false.
+func ebayGrade(descriptors []ebayDescriptor, title string) (string, float64, bool) {
+	rawGrader := ebayDescriptorValue(descriptors, "Professional Grader")
+	rawGrade := ebayDescriptorValue(descriptors, "Grade")
+	if rawGrader == "" {
+		match := ebayGradeTitle.FindStringSubmatch(title)
+		if match == nil {
+			return "", 0, true
+		}
+		rawGrader, rawGrade = match[1], match[2]
+	}
+	// Descriptors spell graders out, e.g. "Professional Sports Authenticator (PSA)"
+	if open := strings.LastIndex(rawGrader, "("); open >= 0 {
+		rawGrader = strings.Trim(rawGrader[open:], "()")
+	}
+
+	grader, ok := grading.NormalizeGrader(rawGrader)
+	if !ok {
+		return "", 0, false
+	}
+	grade, err := grading.ParseGrade(rawGrade)
+	if err != nil {
+		return "", 0, false
+	}
+	return grader, grade, true
+}
@@ -0,0 +1,166 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/conditions"
+)
+
+// fixturesDir holds the recorded marketplace responses shipped with the repo
+var fixturesDir = filepath.Join("..", "..", "data", "ingest")
+
+// fixtureServer serves a source's fixtures over HTTP and records each request
+func fixtureServer(t *testing.T, source string) (*httptest.Server, *[]*http.Request) {
+	t.Helper()
+	var requests []*http.Request
+	handler := FixtureHandler(filepath.Join(fixturesDir, source))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Clone(context.Background()))
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestEBaySales(t *testing.T) {
+	server, requests := fixtureServer(t, "ebay")
+	source := NewEBay(Endpoint{BaseURL: server.URL, Token: "secret"})
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	page, err := source.Sales(context.Background(), "", since)
+	if err != nil {
+		t.Fatalf("Sales: %v", err)
+	}
+	if page.Next != "200" {
+		t.Errorf("Next = %q, want 200", page.Next)
+	}
+	if len(page.Items) != 5 {
+		t.Fatalf("got %d sales, want 5", len(page.Items))
+	}
+
+	req := (*requests)[0]
+	if got := req.Header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization = %q, want bearer token", got)
+	}
+	if got := req.URL.Query().Get("filter"); got != "lastSoldDate:[2026-10-01T00:00:00Z..]" {
+		t.Errorf("filter = %q", got)
+	}
+
+	first := page.Items[0]
+	if first.ID != "v1|315001000001|0@2026-10-02T18:21:07Z" {
+		t.Errorf("ID = %q", first.ID)
+	}
+	if first.ProductID != "19051234567" || first.Price != 74.99 || first.Currency != "USD" || first.Quantity != 1 {
+		t.Errorf("unexpected first sale: %+v", first)
+	}
+	if first.Condition != conditions.NearMint {
+		t.Errorf("Condition = %q, want Near Mint", first.Condition)
+	}
+	if got := page.Items[1].Condition; got != conditions.LightlyPlayed {
+		t.Errorf("Condition = %q, want Lightly Played", got)
+	}
+	if graded := page.Items[2]; graded.Grader != "PSA" || graded.Grade != 10 {
+		t.Errorf("graded sale = %s %v, want PSA 10", graded.Grader, graded.Grade)
+	}
+
+	next, err := source.Sales(context.Background(), page.Next, since)
+	if err != nil {
+		t.Fatalf("Sales page 2: %v", err)
+	}
+	if next.Next != "" {
+		t.Errorf("Next = %q after the last page", next.Next)
+	}
+	// The SGC-graded sale is from an unsupported grader
+	if len(next.Items) != 3 {
+		t.Fatalf("got %d sales on page 2, want 3", len(next.Items))
+	}
+	// A multi-quantity listing selling again gets a new ID
+	if resold := next.Items[2]; resold.ID == first.ID {
+		t.Errorf("repeat sale of %s reused ID %q", resold.ProductID, resold.ID)
+	}
+}
+
+func TestEBayListings(t *testing.T) {
+	server, _ := fixtureServer(t, "ebay")
+	source := NewEBay(Endpoint{BaseURL: server.URL})
+
+	page, err := source.Listings(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Listings: %v", err)
+	}
+	if page.Next != "" || len(page.Items) != 3 {
+		t.Fatalf("got %d listings, next %q; want 3 and no next", len(page.Items), page.Next)
+	}
+
+	first := page.Items[0]
+	if first.ID != "v1|316002000001|0" || first.Seller != "pallet_town_cards" || first.Price != 69.99 || first.ImageURL == "" {
+		t.Errorf("unexpected first listing: %+v", first)
+	}
+	if graded := page.Items[2]; graded.Grader != "CGC" || graded.Grade != 9.5 {
+		t.Errorf("graded listing = %s %v, want CGC 9.5", graded.Grader, graded.Grade)
+	}
+}
+
+func TestEBayProduct(t *testing.T) {
+	server, _ := fixtureServer(t, "ebay")
+	source := NewEBay(Endpoint{BaseURL: server.URL})
+
+	product, err := source.Product(context.Background(), "19051234571")
+	if err != nil {
+		t.Fatalf("Product: %v", err)
+	}
+	want := Product{
+		ID:       "19051234571",
+		Name:     "Charizard VMAX",
+		Set:      "Shiny Star V",
+		Number:   "308/190",
+		Game:     "Pokemon",
+		Printing: Printing{Finish: "foil", Language: "ja"},
+	}
+	if product != want {
+		t.Errorf("Product = %+v, want %+v", product, want)
+	}
+
+	_, err = source.Product(context.Background(), "404")
+	var status *StatusError
+	if !errors.As(err, &status) || status.StatusCode != http.StatusNotFound {
+		t.Errorf("missing product error = %v, want a 404 StatusError", err)
+	}
+}
+
+func TestEBayGrade(t *testing.T) {
+	tests := []struct {
+		name        string
+		descriptors []ebayDescriptor
+		title       string
+		grader      string
+		grade       float64
+		ok          bool
+	}{
+		{name: "raw", title: "Charizard VMAX NM", ok: true},
+		{name: "title", title: "PSA 9 Charizard", grader: "PSA", grade: 9, ok: true},
+		{
+			name:        "descriptors",
+			descriptors: []ebayDescriptor{{Name: "Professional Grader", Values: []string{"Beckett Grading Services (BGS)"}}, {Name: "Grade", Values: []string{"9.5"}}},
+			grader:      "BGS", grade: 9.5, ok: true,
+		},
+		{
+			name:        "unsupported grader",
+			descriptors: []ebayDescriptor{{Name: "Professional Grader", Values: []string{"Sportscard Guaranty (SGC)"}}, {Name: "Grade", Values: []string{"10"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grader, grade, ok := ebayGrade(tt.descriptors, tt.title)
+			if grader != tt.grader || grade != tt.grade || ok != tt.ok {
+				t.Errorf("ebayGrade = %q, %v, %v; want %q, %v, %v", grader, grade, ok, tt.grader, tt.grade, tt.ok)
+			}
+		})
+	}
+}
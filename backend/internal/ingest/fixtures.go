@@ -0,0 +1,78 @@
+package ingest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// pageParams are the query parameters sources page with, checked in order
+var pageParams = []string{"offset", "start", "page", "cursor"}
+
+// FixturePath returns the recorded response file for a request: the URL path
+// under dir with a .json extension, e.g. /v2/sales -> dir/v2/sales.json.
+// Later pages add the page parameter's value: /v2/sales?offset=50 ->
+// dir/v2/sales.50.json. Other query parameters are ignored.
+func FixturePath(dir string, u *url.URL) string {
+	name := path.Clean("/" + u.Path)
+	query := u.Query()
+	for _, param := range pageParams {
+		if value := query.Get(param); value != "" && value != "0" {
+			name += "." + url.PathEscape(value)
+			break
+		}
+	}
+	return filepath.Join(dir, filepath.FromSlash(name)+".json")
+}
+
+// FixtureTransport answers requests from recorded responses under dir, so
+// sources run offline against the same client code. Missing files are 404s.
+type FixtureTransport struct {
+	Dir string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t FixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	status := http.StatusOK
+	body, err := os.ReadFile(FixturePath(t.Dir, req.URL))
+	if os.IsNotExist(err) {
+		status, body = http.StatusNotFound, []byte(`{"error":"no fixture"}`)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %v", err)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// FixtureHandler serves recorded responses under dir over HTTP, a local
+// stand-in for a marketplace API
+func FixtureHandler(dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		file := FixturePath(dir, r.URL)
+		if _, err := os.Stat(file); err != nil {
+			http.Error(w, `{"error":"no fixture"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		http.ServeFile(w, r, file)
+	})
+}
@@ -0,0 +1,284 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// Mapper resolves a source's product IDs to cards. Mappings are kept in
+// external_products; unknown products are looked up from the source and
+// matched by name and set, narrowed by number and game when given.
+type Mapper struct {
+	db     *mongo.Database
+	source PriceSource
+	cards  map[string]*mappedProduct // By external ID; nil card means unmatched
+}
+
+// LookupError is a failure to look a product up from its source
+type LookupError struct {
+	Source    string
+	ProductID string
+	Err       error
+}
+
+func (e *LookupError) Error() string {
+	return fmt.Sprintf("failed to look up %s product %s: %v", e.Source, e.ProductID, e.Err)
+}
+
+func (e *LookupError) Unwrap() error { return e.Err }
+
+// mappedProduct is a resolved card and the printing its product describes
+type mappedProduct struct {
+	card     *models.Card
+	printing Printing
+}
+
+// NewMapper creates a mapper for one source
+func NewMapper(db *mongo.Database, source PriceSource) *Mapper {
+	return &Mapper{db: db, source: source, cards: make(map[string]*mappedProduct)}
+}
+
+// Resolve returns the card and variant a record of the product in the given
+// printing belongs to. The card is nil when the product matches no card or
+// the card has no such printing.
+func (m *Mapper) Resolve(ctx context.Context, productID string, printing Printing) (*models.Card, *primitive.ObjectID, error) {
+	mapped, err := m.product(ctx, productID)
+	if err != nil || mapped.card == nil {
+		return nil, nil, err
+	}
+
+	variantID, ok := matchVariant(*mapped.card, mapped.printing.merge(printing))
+	if !ok {
+		return nil, nil, nil
+	}
+	return mapped.card, variantID, nil
+}
+
+// product returns the product's mapping. Products seen before are read from
+// external_products, and re-matched if they matched no card then; new ones
+// are looked up from the source, matched and stored.
+func (m *Mapper) product(ctx context.Context, productID string) (*mappedProduct, error) {
+	if mapped, ok := m.cards[productID]; ok {
+		return mapped, nil
+	}
+
+	collection := m.db.Collection("external_products")
+	filter := bson.M{"source": m.source.Name(), "external_id": productID}
+
+	var stored models.ExternalProduct
+	err := collection.FindOne(ctx, filter).Decode(&stored)
+	switch {
+	case err == mongo.ErrNoDocuments:
+		product, err := m.source.Product(ctx, productID)
+		var status *StatusError
+		if errors.As(err, &status) && status.StatusCode == http.StatusNotFound {
+			// Delisted from the catalog; skip the product for this run
+			m.cards[productID] = &mappedProduct{}
+			return m.cards[productID], nil
+		}
+		if err != nil {
+			return nil, &LookupError{Source: m.source.Name(), ProductID: productID, Err: err}
+		}
+		stored = models.ExternalProduct{
+			Source:     m.source.Name(),
+			ExternalID: productID,
+			Name:       product.Name,
+			Set:        product.Set,
+			Number:     product.Number,
+			Game:       product.Game,
+			Finish:     product.Printing.Finish,
+			Edition:    product.Printing.Edition,
+			Language:   product.Printing.Language,
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to load product mapping: %v", err)
+	}
+
+	mapped := &mappedProduct{printing: Printing{Finish: stored.Finish, Edition: stored.Edition, Language: stored.Language}}
+	if stored.CardID != nil || stored.Manual {
+		if mapped.card, err = m.loadCard(ctx, stored.CardID); err != nil {
+			return nil, err
+		}
+		m.cards[productID] = mapped
+		return mapped, nil
+	}
+
+	product := Product{ID: productID, Name: stored.Name, Set: stored.Set, Number: stored.Number, Game: stored.Game}
+	if mapped.card, err = m.match(ctx, product); err != nil {
+		return nil, err
+	}
+	if mapped.card != nil {
+		stored.CardID = &mapped.card.ID
+	}
+	stored.UpdatedAt = time.Now().UTC()
+	if _, err := collection.UpdateOne(ctx, filter, bson.M{"$set": stored}, options.Update().SetUpsert(true)); err != nil {
+		return nil, fmt.Errorf("failed to save product mapping: %v", err)
+	}
+
+	m.cards[productID] = mapped
+	return mapped, nil
+}
+
+// loadCard loads a mapped card with its variants; a nil ID is an unmatched product
+func (m *Mapper) loadCard(ctx context.Context, id *primitive.ObjectID) (*models.Card, error) {
+	if id == nil {
+		return nil, nil
+	}
+	var card models.Card
+	err := m.db.Collection("cards").FindOne(ctx, bson.M{"_id": *id}, options.FindOne().SetProjection(cardProjection)).Decode(&card)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mapped card: %v", err)
+	}
+	return &card, nil
+}
+
+// cardProjection holds the card fields matching needs
+var cardProjection = bson.M{"name": 1, "set": 1, "number": 1, "game": 1, "variants": 1}
+
+// match finds the one card a product describes, or nil when none or several do
+func (m *Mapper) match(ctx context.Context, product Product) (*models.Card, error) {
+	if product.Name == "" {
+		return nil, nil
+	}
+	filter := bson.M{"name": exactly(product.Name)}
+	if product.Set != "" {
+		filter["set"] = exactly(product.Set)
+	}
+	if product.Game != "" {
+		filter["game"] = exactly(product.Game)
+	}
+
+	cursor, err := m.db.Collection("cards").Find(ctx, filter, options.Find().SetProjection(cardProjection))
+	if err != nil {
+		return nil, fmt.Errorf("failed to match cards: %v", err)
+	}
+	var candidates []models.Card
+	if err := cursor.All(ctx, &candidates); err != nil {
+		return nil, fmt.Errorf("failed to decode cards: %v", err)
+	}
+
+	// Numbers disambiguate reprints within a set; catalogs format them
+	// differently ("25/102" vs "025/102"), so only the digits are compared
+	if len(candidates) > 1 && product.Number != "" {
+		numbered := candidates[:0]
+		for _, c := range candidates {
+			if sameNumber(c.Number, product.Number) {
+				numbered = append(numbered, c)
+			}
+		}
+		candidates = numbered
+	}
+	if len(candidates) != 1 {
+		return nil, nil
+	}
+	return &candidates[0], nil
+}
+
+// matchVariant returns the card variant for a printing: the default variant
+// when the printing is unspecified, otherwise the variant with the same
+// finish and language (and edition when given), preferring the default.
+// Cards whose printings haven't been catalogued keep every printing on their
+// default variant (or unassigned before migration), so only the language and
+// edition have to agree.
+func matchVariant(card models.Card, printing Printing) (*primitive.ObjectID, bool) {
+	if len(card.Variants) <= 1 {
+		var only models.CardVariant
+		if len(card.Variants) == 1 {
+			only = card.Variants[0]
+		}
+		if !matches(only.Language, printing.Language, "en") || (printing.Edition != "" && printing.Edition != only.Edition) {
+			return nil, false
+		}
+		if len(card.Variants) == 0 {
+			return nil, true
+		}
+		return &card.Variants[0].ID, true
+	}
+
+	var found *models.CardVariant
+	for i := range card.Variants {
+		v := &card.Variants[i]
+		if printing == (Printing{}) {
+			if v.IsDefault {
+				return &v.ID, true
+			}
+			continue
+		}
+		if !matches(v.Finish, printing.Finish, "normal") || !matches(v.Language, printing.Language, "en") {
+			continue
+		}
+		if printing.Edition != "" && v.Edition != printing.Edition {
+			continue
+		}
+		if found == nil || v.IsDefault {
+			found = v
+		}
+	}
+	if found == nil {
+		return nil, false
+	}
+	return &found.ID, true
+}
+
+// merge fills a record's unspecified printing fields from the product's
+func (p Printing) merge(record Printing) Printing {
+	if record.Finish == "" {
+		record.Finish = p.Finish
+	}
+	if record.Edition == "" {
+		record.Edition = p.Edition
+	}
+	if record.Language == "" {
+		record.Language = p.Language
+	}
+	return record
+}
+
+// matches compares a variant attribute with a printing's, treating empty
+// values on either side as the fallback
+func matches(variant, printing, fallback string) bool {
+	if variant == "" {
+		variant = fallback
+	}
+	if printing == "" {
+		printing = fallback
+	}
+	return variant == printing
+}
+
+// exactly matches a string field case-insensitively
+func exactly(value string) bson.M {
+	return bson.M{"$regex": "^" + regexp.QuoteMeta(strings.TrimSpace(value)) + "$", "$options": "i"}
+}
+
+// sameNumber compares collector numbers ignoring leading zeros and formatting
+func sameNumber(a, b string) bool {
+	return digits(a) == digits(b)
+}
+
+// digitGroups finds the runs of digits in a collector number
+var digitGroups = regexp.MustCompile(`\d+`)
+
+// digits returns the number's digit groups without leading zeros, e.g. "025/102" -> "25/102"
+func digits(number string) string {
+	groups := digitGroups.FindAllString(number, -1)
+	for i, g := range groups {
+		groups[i] = strings.TrimLeft(g, "0")
+	}
+	return strings.Join(groups, "/")
+}
@@ -0,0 +1,81 @@
+package ingest
+
+import (
+	"strings"
+
+	"github.com/jamesc159/monmetrics/internal/conditions"
+)
+
+// games maps marketplace game names (lowercase) to catalog names
+var games = map[string]string{
+	"pokemon": "Pokemon", "pokémon": "Pokemon", "pokemon tcg": "Pokemon",
+	"magic": "Magic The Gathering", "magic: the gathering": "Magic The Gathering", "magic the gathering": "Magic The Gathering", "mtg": "Magic The Gathering",
+	"yu-gi-oh": "Yu-Gi-Oh", "yu-gi-oh!": "Yu-Gi-Oh", "yugioh": "Yu-Gi-Oh", "yu-gi-oh! tcg": "Yu-Gi-Oh",
+}
+
+// gameName returns the catalog name of a marketplace's game, or the name as
+// given when unknown
+func gameName(raw string) string {
+	if name, ok := games[strings.ToLower(strings.TrimSpace(raw))]; ok {
+		return name
+	}
+	return strings.TrimSpace(raw)
+}
+
+// finishes maps marketplace finish and printing names (lowercase) to variant finishes
+var finishes = map[string]string{
+	"normal": "normal", "non-holo": "normal", "regular": "normal",
+	"holo": "foil", "holofoil": "foil", "foil": "foil",
+	"reverse holo": "reverse_holo", "reverse holofoil": "reverse_holo", "reverse_holo": "reverse_holo",
+	"etched": "etched", "etched foil": "etched",
+}
+
+// finishOf returns the variant finish for a marketplace's finish name, empty
+// when unspecified or unknown
+func finishOf(raw string) string {
+	return finishes[strings.ToLower(strings.TrimSpace(raw))]
+}
+
+// languages maps language names (lowercase) to ISO 639-1 codes
+var languages = map[string]string{
+	"english": "en", "japanese": "ja", "german": "de", "french": "fr", "italian": "it",
+	"spanish": "es", "portuguese": "pt", "korean": "ko", "chinese": "zh", "russian": "ru",
+}
+
+// languageOf returns the ISO 639-1 code for a language name or code, empty
+// when unspecified or unknown
+func languageOf(raw string) string {
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if code, ok := languages[raw]; ok {
+		return code
+	}
+	for _, code := range languages {
+		if raw == code {
+			return code
+		}
+	}
+	return ""
+}
+
+// conditionOf maps a marketplace's condition wording to a canonical
+// condition, e.g. eBay's "Near mint or better" or "Lightly played
+// (Excellent)". Unknown wording is left empty, which reads as Near Mint.
+func conditionOf(raw string) string {
+	if condition, ok := conditions.Normalize(raw); ok {
+		return condition
+	}
+	lower := strings.ToLower(raw)
+	switch {
+	case strings.Contains(lower, "near mint"):
+		return conditions.NearMint
+	case strings.Contains(lower, "lightly"), strings.Contains(lower, "excellent"):
+		return conditions.LightlyPlayed
+	case strings.Contains(lower, "moderately"), strings.Contains(lower, "very good"):
+		return conditions.ModeratelyPlayed
+	case strings.Contains(lower, "heavily"), strings.Contains(lower, "poor"):
+		return conditions.HeavilyPlayed
+	case strings.Contains(lower, "damaged"):
+		return conditions.Damaged
+	}
+	return ""
+}
@@ -0,0 +1,487 @@
+package ingest
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/fx"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// Feed kinds tracked by checkpoints
+const (
+	KindSales    = "sales"
+	KindListings = "listings"
+)
+
+// Options tune how a Runner reads sources
+type Options struct {
+	MaxRetries int           // Retries per request after the first attempt
+	Backoff    time.Duration // Wait before the first retry, doubling after each
+	MaxPages   int           // Pages per feed per run; 0 reads to the end
+	Lookback   time.Duration // How far back a source's first sales pass reaches
+}
+
+// DefaultOptions are used for unset options
+var DefaultOptions = Options{
+	MaxRetries: 3,
+	Backoff:    time.Second,
+	Lookback:   30 * 24 * time.Hour,
+}
+
+// Result summarizes an ingestion run for one source
+type Result struct {
+	Source     string `json:"source"`
+	Pages      int    `json:"pages"`
+	Sales      int    `json:"sales"`      // New sales stored
+	Duplicates int    `json:"duplicates"` // Sales already stored
+	Listings   int    `json:"listings"`   // Asks inserted or updated
+	Removed    int    `json:"removed"`    // Asks no longer on the marketplace
+	Unmapped   int    `json:"unmapped"`   // Records whose product matched no card or printing
+	Skipped    int    `json:"skipped"`    // Records without a price, date or supported currency
+
+	// Dirty holds, per card, the earliest new sale so daily aggregates
+	// already built for those days can be rebuilt
+	Dirty map[primitive.ObjectID]time.Time `json:"-"`
+}
+
+// Runner pages through sources into prices and listings. Progress is
+// checkpointed after every page so an interrupted run resumes where it
+// stopped; sales are deduplicated by marketplace ID and listings upserted,
+// so re-reading a page is harmless.
+type Runner struct {
+	db   *mongo.Database
+	opts Options
+}
+
+// NewRunner creates a Runner, filling unset options from DefaultOptions
+func NewRunner(db *mongo.Database, opts Options) *Runner {
+	if opts.MaxRetries <= 0 {
+		opts.MaxRetries = DefaultOptions.MaxRetries
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultOptions.Backoff
+	}
+	if opts.Lookback <= 0 {
+		opts.Lookback = DefaultOptions.Lookback
+	}
+	return &Runner{db: db, opts: opts}
+}
+
+// Run ingests a source's new sales, then its current listings
+func (r *Runner) Run(ctx context.Context, source PriceSource) (Result, error) {
+	result := Result{Source: source.Name(), Dirty: make(map[primitive.ObjectID]time.Time)}
+	mapper := NewMapper(r.db, source)
+
+	if err := r.runSales(ctx, source, mapper, &result); err != nil {
+		return result, fmt.Errorf("%s sales: %w", source.Name(), err)
+	}
+	if err := r.runListings(ctx, source, mapper, &result); err != nil {
+		return result, fmt.Errorf("%s listings: %w", source.Name(), err)
+	}
+	return result, nil
+}
+
+// Reset clears a source's checkpoints so its next run starts over, reading
+// sales from the lookback window and listings from the first page
+func (r *Runner) Reset(ctx context.Context, source string) error {
+	_, err := r.db.Collection("ingest_checkpoints").DeleteMany(ctx, bson.M{"source": source})
+	if err != nil {
+		return fmt.Errorf("failed to reset checkpoints: %v", err)
+	}
+	return nil
+}
+
+// runSales reads sales since the newest one seen by the last completed pass
+func (r *Runner) runSales(ctx context.Context, source PriceSource, mapper *Mapper, result *Result) error {
+	now := time.Now().UTC()
+	cp, err := r.checkpoint(ctx, source.Name(), KindSales)
+	if err != nil {
+		return err
+	}
+	if cp.Cursor == "" {
+		cp.Since = cp.Newest
+		if cp.Since.IsZero() {
+			cp.Since = now.Add(-r.opts.Lookback)
+		}
+		cp.PassStarted = now
+		cp.Pages = 0
+	}
+
+	for pages := 0; r.opts.MaxPages == 0 || pages < r.opts.MaxPages; pages++ {
+		var page Page[Sale]
+		err := r.retry(ctx, func() error {
+			var err error
+			page, err = source.Sales(ctx, cp.Cursor, cp.Since)
+			return err
+		})
+		if errors.Is(err, ErrNotSupported) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := r.storeSales(ctx, source.Name(), mapper, page.Items, &cp, result); err != nil {
+			return err
+		}
+		result.Pages++
+		if err := r.advance(ctx, &cp, page.Next); err != nil {
+			return err
+		}
+		if page.Next == "" {
+			break
+		}
+	}
+	return nil
+}
+
+// runListings reads every current ask, then removes the source's asks the
+// completed pass didn't see
+func (r *Runner) runListings(ctx context.Context, source PriceSource, mapper *Mapper, result *Result) error {
+	cp, err := r.checkpoint(ctx, source.Name(), KindListings)
+	if err != nil {
+		return err
+	}
+	if cp.Cursor == "" {
+		cp.PassStarted = time.Now().UTC()
+		cp.Pages = 0
+	}
+
+	for pages := 0; r.opts.MaxPages == 0 || pages < r.opts.MaxPages; pages++ {
+		var page Page[Listing]
+		err := r.retry(ctx, func() error {
+			var err error
+			page, err = source.Listings(ctx, cp.Cursor)
+			return err
+		})
+		if errors.Is(err, ErrNotSupported) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := r.storeListings(ctx, source.Name(), mapper, page.Items, result); err != nil {
+			return err
+		}
+		result.Pages++
+		if page.Next == "" {
+			removed, err := r.db.Collection("listings").DeleteMany(ctx, bson.M{
+				"source":      source.Name(),
+				"external_id": bson.M{"$exists": true},
+				"updated_at":  bson.M{"$lt": cp.PassStarted},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to remove stale listings: %v", err)
+			}
+			result.Removed += int(removed.DeletedCount)
+		}
+		if err := r.advance(ctx, &cp, page.Next); err != nil {
+			return err
+		}
+		if page.Next == "" {
+			break
+		}
+	}
+	return nil
+}
+
+// storeSales inserts sales not stored before, keyed by source and marketplace ID
+func (r *Runner) storeSales(ctx context.Context, source string, mapper *Mapper, sales []Sale, cp *models.IngestCheckpoint, result *Result) error {
+	points, err := r.prepareSales(ctx, source, mapper, sales, cp, result)
+	if err != nil {
+		return err
+	}
+	if len(points) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(points))
+	for _, point := range points {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"source": source, "external_id": point.ExternalID}).
+			SetUpdate(bson.M{"$setOnInsert": point}).
+			SetUpsert(true))
+	}
+
+	res, err := r.db.Collection("prices").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	// A concurrent run may insert the same sale first; the unique index keeps one
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to store sales: %v", err)
+	}
+	if res == nil {
+		return nil
+	}
+
+	for i := range res.UpsertedIDs {
+		p := points[i]
+		if since, ok := result.Dirty[p.CardID]; !ok || p.Timestamp.Before(since) {
+			result.Dirty[p.CardID] = p.Timestamp
+		}
+	}
+	result.Sales += int(res.UpsertedCount)
+	result.Duplicates += len(writes) - int(res.UpsertedCount)
+	return nil
+}
+
+// prepareSales turns a page of sales into price points for the cards they
+// map to, advancing the checkpoint's newest sale. Sales without a price, date
+// or supported currency are skipped, repeats within the page are counted as
+// duplicates and sales of unknown products as unmapped.
+func (r *Runner) prepareSales(ctx context.Context, source string, cards resolver, sales []Sale, cp *models.IngestCheckpoint, result *Result) ([]models.PricePoint, error) {
+	now := time.Now().UTC()
+	seen := make(map[string]bool, len(sales))
+	var points []models.PricePoint
+	for _, s := range sales {
+		if s.SoldAt.After(cp.Newest) {
+			cp.Newest = s.SoldAt.UTC()
+		}
+
+		currency, ok := r.currency(source, s.Currency)
+		if !ok || s.Price <= 0 || s.SoldAt.IsZero() {
+			result.Skipped++
+			continue
+		}
+
+		id := s.ID
+		if id == "" {
+			id = saleKey(s)
+		}
+		if seen[id] {
+			result.Duplicates++
+			continue
+		}
+		seen[id] = true
+
+		card, variantID, err := r.resolve(ctx, cards, s.ProductID, s.Printing)
+		if err != nil {
+			return nil, err
+		}
+		if card == nil {
+			result.Unmapped++
+			continue
+		}
+
+		volume := s.Quantity
+		if volume <= 0 {
+			volume = 1
+		}
+		points = append(points, models.PricePoint{
+			CardID:     card.ID,
+			VariantID:  variantID,
+			Price:      s.Price,
+			Currency:   currency,
+			Volume:     volume,
+			Source:     source,
+			ExternalID: id,
+			Timestamp:  s.SoldAt.UTC(),
+			CreatedAt:  now,
+			Condition:  s.Condition,
+			Grader:     s.Grader,
+			Grade:      s.Grade,
+		})
+	}
+	return points, nil
+}
+
+// storeListings upserts asks keyed by source and marketplace ID
+func (r *Runner) storeListings(ctx context.Context, source string, mapper *Mapper, listings []Listing, result *Result) error {
+	now := time.Now().UTC()
+	var writes []mongo.WriteModel
+	for _, l := range listings {
+		currency, ok := r.currency(source, l.Currency)
+		if !ok || l.Price <= 0 || l.ID == "" {
+			result.Skipped++
+			continue
+		}
+
+		card, variantID, err := r.resolve(ctx, mapper, l.ProductID, l.Printing)
+		if err != nil {
+			return err
+		}
+		if card == nil {
+			result.Unmapped++
+			continue
+		}
+
+		quantity := l.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		title := l.Title
+		if title == "" {
+			title = card.Name + " - " + card.Set
+		}
+		set := bson.M{
+			"card_id":    card.ID,
+			"title":      title,
+			"price":      l.Price,
+			"currency":   currency,
+			"quantity":   quantity,
+			"condition":  l.Condition,
+			"seller":     l.Seller,
+			"source":     source,
+			"updated_at": now,
+		}
+		// Fields stored with omitempty are removed rather than set empty
+		unset := bson.M{}
+		if variantID != nil {
+			set["variant_id"] = *variantID
+		} else {
+			unset["variant_id"] = ""
+		}
+		if l.Grader != "" {
+			set["grader"], set["grade"] = l.Grader, l.Grade
+		} else {
+			unset["grader"], unset["grade"] = "", ""
+		}
+		if l.ImageURL != "" {
+			set["image_url"] = l.ImageURL
+		} else {
+			unset["image_url"] = ""
+		}
+		created := now
+		if !l.ListedAt.IsZero() {
+			created = l.ListedAt.UTC()
+		}
+
+		update := bson.M{"$set": set, "$setOnInsert": bson.M{"created_at": created}}
+		if len(unset) > 0 {
+			update["$unset"] = unset
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"source": source, "external_id": l.ID}).
+			SetUpdate(update).
+			SetUpsert(true))
+	}
+	if len(writes) == 0 {
+		return nil
+	}
+
+	res, err := r.db.Collection("listings").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil && !mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("failed to store listings: %v", err)
+	}
+	if res != nil {
+		result.Listings += int(res.UpsertedCount + res.ModifiedCount)
+	}
+	return nil
+}
+
+// resolver maps a source's product in a printing to a card and variant; a
+// nil card means the product matched none
+type resolver interface {
+	Resolve(ctx context.Context, productID string, printing Printing) (*models.Card, *primitive.ObjectID, error)
+}
+
+// resolve maps a product to a card, retrying lookups that fail temporarily.
+// Products the source can't describe are reported as unmapped (nil card).
+func (r *Runner) resolve(ctx context.Context, cards resolver, productID string, printing Printing) (*models.Card, *primitive.ObjectID, error) {
+	var (
+		card      *models.Card
+		variantID *primitive.ObjectID
+	)
+	err := r.retry(ctx, func() error {
+		var err error
+		card, variantID, err = cards.Resolve(ctx, productID, printing)
+		return err
+	})
+	var lookup *LookupError
+	if errors.As(err, &lookup) && !retryable(err) {
+		return nil, nil, nil
+	}
+	return card, variantID, err
+}
+
+// currency returns the record's currency, defaulting to the marketplace's,
+// and false when it isn't supported
+func (r *Runner) currency(source, currency string) (string, bool) {
+	if currency == "" {
+		return fx.ForSource(source), true
+	}
+	return fx.Normalize(currency)
+}
+
+// retry calls fetch until it succeeds, fails permanently or runs out of
+// retries, backing off exponentially or as long as the API asks
+func (r *Runner) retry(ctx context.Context, fetch func() error) error {
+	delay := r.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		err := fetch()
+		if err == nil || !retryable(err) || attempt >= r.opts.MaxRetries {
+			return err
+		}
+
+		wait := delay
+		var status *StatusError
+		if errors.As(err, &status) && status.RetryAfter > wait {
+			wait = status.RetryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// checkpoint loads a feed's checkpoint, or a fresh one on its first run
+func (r *Runner) checkpoint(ctx context.Context, source, kind string) (models.IngestCheckpoint, error) {
+	cp := models.IngestCheckpoint{Source: source, Kind: kind}
+	err := r.db.Collection("ingest_checkpoints").FindOne(ctx, bson.M{"source": source, "kind": kind}).Decode(&cp)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return cp, fmt.Errorf("failed to load checkpoint: %v", err)
+	}
+	return cp, nil
+}
+
+// advance records a stored page: the next page to read, or the pass as
+// complete when there is none
+func (r *Runner) advance(ctx context.Context, cp *models.IngestCheckpoint, next string) error {
+	now := time.Now().UTC()
+	cp.Cursor = next
+	cp.Pages++
+	cp.UpdatedAt = now
+	if next == "" {
+		cp.CompletedAt = now
+	}
+
+	_, err := r.db.Collection("ingest_checkpoints").UpdateOne(ctx,
+		bson.M{"source": cp.Source, "kind": cp.Kind},
+		bson.M{"$set": bson.M{
+			"cursor":       cp.Cursor,
+			"since":        cp.Since,
+			"newest":       cp.Newest,
+			"pass_started": cp.PassStarted,
+			"pages":        cp.Pages,
+			"completed_at": cp.CompletedAt,
+			"updated_at":   cp.UpdatedAt,
+		}},
+		options.Update().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %v", err)
+	}
+	return nil
+}
+
+// saleKey derives a stable ID for a sale the marketplace reports without one
+func saleKey(s Sale) string {
+	key := fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s|%s|%s|%s|%d",
+		s.ProductID, s.Printing.Finish, s.Printing.Edition, s.Printing.Language,
+		s.SoldAt.UTC().Format(time.RFC3339Nano), strconv.FormatFloat(s.Price, 'f', -1, 64), s.Currency,
+		s.Condition, s.Grader, strconv.FormatFloat(s.Grade, 'f', -1, 64), s.Quantity)
+	sum := sha1.Sum([]byte(key))
+	return "derived:" + hex.EncodeToString(sum[:])
+}
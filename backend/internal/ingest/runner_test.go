@@ -0,0 +1,220 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// fakeResolver maps product IDs to cards without a database. Products in
+// errs fail with that error; the rest are unknown.
+type fakeResolver struct {
+	cards map[string]*models.Card
+	errs  map[string]error
+	calls int
+}
+
+func (f *fakeResolver) Resolve(ctx context.Context, productID string, printing Printing) (*models.Card, *primitive.ObjectID, error) {
+	f.calls++
+	if err, ok := f.errs[productID]; ok {
+		return nil, nil, err
+	}
+	return f.cards[productID], nil, nil
+}
+
+func newTestRunner() *Runner {
+	return NewRunner(nil, Options{MaxRetries: 2, Backoff: time.Millisecond})
+}
+
+func newTestResolver() (*fakeResolver, *models.Card) {
+	card := &models.Card{ID: primitive.NewObjectID(), Name: "Charizard VMAX"}
+	return &fakeResolver{cards: map[string]*models.Card{"p1": card}}, card
+}
+
+func TestPrepareSalesDedupe(t *testing.T) {
+	cards, _ := newTestResolver()
+	soldAt := time.Date(2026, 10, 2, 18, 21, 7, 0, time.UTC)
+	sales := []Sale{
+		{ID: "a", ProductID: "p1", Price: 10, SoldAt: soldAt},
+		{ID: "a", ProductID: "p1", Price: 10, SoldAt: soldAt},
+		// Without marketplace IDs, identical sales share a derived key...
+		{ProductID: "p1", Price: 12, Quantity: 2, SoldAt: soldAt},
+		{ProductID: "p1", Price: 12, Quantity: 2, SoldAt: soldAt},
+		// ...and any differing field gives a new one
+		{ProductID: "p1", Price: 12.5, Quantity: 2, SoldAt: soldAt},
+	}
+
+	var result Result
+	var cp models.IngestCheckpoint
+	points, err := newTestRunner().prepareSales(context.Background(), "tcgplayer", cards, sales, &cp, &result)
+	if err != nil {
+		t.Fatalf("prepareSales: %v", err)
+	}
+	if len(points) != 3 || result.Duplicates != 2 {
+		t.Fatalf("got %d points and %d duplicates, want 3 and 2", len(points), result.Duplicates)
+	}
+	if points[0].ExternalID != "a" {
+		t.Errorf("ExternalID = %q, want the marketplace ID", points[0].ExternalID)
+	}
+	if points[1].ExternalID != saleKey(sales[2]) || points[1].ExternalID == points[2].ExternalID {
+		t.Errorf("derived IDs = %q, %q", points[1].ExternalID, points[2].ExternalID)
+	}
+	if points[1].Volume != 2 || points[0].Volume != 1 {
+		t.Errorf("volumes = %d, %d; want 1 and 2", points[0].Volume, points[1].Volume)
+	}
+}
+
+func TestSaleKey(t *testing.T) {
+	sale := Sale{ProductID: "226584", Price: 72.49, Quantity: 1, Condition: "Near Mint", SoldAt: time.Date(2026, 10, 3, 19, 14, 0, 0, time.UTC)}
+
+	// The same instant in another zone is the same sale
+	local := sale
+	local.SoldAt = sale.SoldAt.In(time.FixedZone("CEST", 2*60*60))
+	if saleKey(sale) != saleKey(local) {
+		t.Error("saleKey depends on the time zone")
+	}
+
+	graded := sale
+	graded.Grader, graded.Grade = "PSA", 10
+	if saleKey(sale) == saleKey(graded) {
+		t.Error("saleKey ignores the grade")
+	}
+}
+
+func TestPrepareSalesCurrency(t *testing.T) {
+	cards, _ := newTestResolver()
+	soldAt := time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		source   string
+		currency string
+		want     string // Empty when the sale is skipped
+	}{
+		{source: "ebay", want: "USD"},
+		{source: "cardmarket", want: "EUR"},
+		{source: "ebay", currency: " gbp ", want: "GBP"},
+		{source: "cardmarket", currency: "USD", want: "USD"},
+		{source: "ebay", currency: "XYZ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source+"/"+tt.currency, func(t *testing.T) {
+			var result Result
+			var cp models.IngestCheckpoint
+			sales := []Sale{{ID: "s", ProductID: "p1", Price: 10, Currency: tt.currency, SoldAt: soldAt}}
+			points, err := newTestRunner().prepareSales(context.Background(), tt.source, cards, sales, &cp, &result)
+			if err != nil {
+				t.Fatalf("prepareSales: %v", err)
+			}
+
+			if tt.want == "" {
+				if len(points) != 0 || result.Skipped != 1 {
+					t.Errorf("got %d points and %d skipped, want the sale skipped", len(points), result.Skipped)
+				}
+				return
+			}
+			if len(points) != 1 || points[0].Currency != tt.want {
+				t.Fatalf("points = %+v, want one in %s", points, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareSalesSkipsInvalid(t *testing.T) {
+	cards, _ := newTestResolver()
+	newest := time.Date(2026, 10, 9, 0, 0, 0, 0, time.UTC)
+	sales := []Sale{
+		{ID: "free", ProductID: "p1", SoldAt: newest.Add(-time.Hour)},
+		{ID: "undated", ProductID: "p1", Price: 10},
+		{ID: "late", ProductID: "p1", Price: -1, SoldAt: newest},
+	}
+
+	var result Result
+	var cp models.IngestCheckpoint
+	points, err := newTestRunner().prepareSales(context.Background(), "ebay", cards, sales, &cp, &result)
+	if err != nil {
+		t.Fatalf("prepareSales: %v", err)
+	}
+	if len(points) != 0 || result.Skipped != 3 {
+		t.Errorf("got %d points and %d skipped, want all 3 skipped", len(points), result.Skipped)
+	}
+	// Skipped sales still move the checkpoint so they aren't re-read
+	if !cp.Newest.Equal(newest) {
+		t.Errorf("Newest = %v, want %v", cp.Newest, newest)
+	}
+	if cards.calls != 0 {
+		t.Errorf("resolved %d skipped sales", cards.calls)
+	}
+}
+
+func TestPrepareSalesUnknownCard(t *testing.T) {
+	cards, card := newTestResolver()
+	cards.errs = map[string]error{
+		"bad": &LookupError{Source: "ebay", ProductID: "bad", Err: &StatusError{StatusCode: http.StatusBadRequest}},
+	}
+	soldAt := time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)
+	sales := []Sale{
+		{ID: "1", ProductID: "unknown", Price: 10, SoldAt: soldAt},
+		{ID: "2", ProductID: "bad", Price: 10, SoldAt: soldAt},
+		{ID: "3", ProductID: "p1", Price: 10, SoldAt: soldAt},
+	}
+
+	var result Result
+	var cp models.IngestCheckpoint
+	points, err := newTestRunner().prepareSales(context.Background(), "ebay", cards, sales, &cp, &result)
+	if err != nil {
+		t.Fatalf("prepareSales: %v", err)
+	}
+	if result.Unmapped != 2 {
+		t.Errorf("Unmapped = %d, want 2", result.Unmapped)
+	}
+	if len(points) != 1 || points[0].CardID != card.ID || points[0].Source != "ebay" {
+		t.Errorf("points = %+v, want one for the known card", points)
+	}
+	// A lookup the source rejects outright isn't retried
+	if cards.calls != 3 {
+		t.Errorf("resolved %d times, want 3", cards.calls)
+	}
+}
+
+func TestPrepareSalesLookupOutage(t *testing.T) {
+	cards, _ := newTestResolver()
+	outage := &LookupError{Source: "ebay", ProductID: "down", Err: &StatusError{StatusCode: http.StatusServiceUnavailable}}
+	cards.errs = map[string]error{"down": outage}
+	sales := []Sale{{ID: "1", ProductID: "down", Price: 10, SoldAt: time.Now()}}
+
+	var result Result
+	var cp models.IngestCheckpoint
+	_, err := newTestRunner().prepareSales(context.Background(), "ebay", cards, sales, &cp, &result)
+	if !errors.Is(err, outage) {
+		t.Fatalf("error = %v, want the lookup failure", err)
+	}
+	// One attempt and two retries
+	if cards.calls != 3 {
+		t.Errorf("resolved %d times, want 3", cards.calls)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	runner := newTestRunner()
+	attempts := 0
+	start := time.Now()
+	err := runner.retry(context.Background(), func() error {
+		attempts++
+		if attempts == 1 {
+			return &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 20 * time.Millisecond}
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("retry = %v after %d attempts, want success on the second", err, attempts)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("retried after %v, before Retry-After", elapsed)
+	}
+}
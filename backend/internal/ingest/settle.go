@@ -0,0 +1,60 @@
+package ingest
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/jamesc159/monmetrics/internal/aggregator"
+	"github.com/jamesc159/monmetrics/internal/outliers"
+	"github.com/jamesc159/monmetrics/internal/pricestats"
+)
+
+// Settled summarizes the follow-up work on cards that received new sales
+type Settled struct {
+	Flagged int // Sales flagged as outliers on those cards
+	Candles int // Daily candles rebuilt
+	Updated int // Cards whose current price or ATH/ATL changed
+}
+
+// Settle brings a run's new sales into the derived data before the next
+// scheduled jobs: each dirty card is scored for outliers, its daily candles
+// are rebuilt from the earliest affected day and its price summary is
+// recomputed, so suspect sales never reach market_data
+func Settle(ctx context.Context, detector *outliers.Detector, agg *aggregator.Aggregator, updater *pricestats.Updater, dirty map[primitive.ObjectID]time.Time) (Settled, error) {
+	var settled Settled
+
+	since := make(map[primitive.ObjectID]time.Time, len(dirty))
+	for cardID, from := range dirty {
+		scored, err := detector.ScoreCard(ctx, cardID)
+		if err != nil {
+			return settled, err
+		}
+		settled.Flagged += scored.Flagged
+
+		// New sales can flip flags on later ones
+		if changed, ok := scored.Dirty[cardID]; ok && changed.Before(from) {
+			from = changed
+		}
+		since[cardID] = from
+	}
+
+	rebuilt, err := agg.Rebuild(ctx, since)
+	if err != nil {
+		return settled, err
+	}
+	settled.Candles = rebuilt.Candles
+
+	for cardID := range since {
+		change, err := updater.UpdateCard(ctx, cardID)
+		if err != nil {
+			return settled, err
+		}
+		if change != nil {
+			settled.Updated++
+		}
+	}
+
+	return settled, nil
+}
@@ -0,0 +1,83 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotSupported is returned by sources that don't publish a kind of data,
+// e.g. a marketplace without a sales feed
+var ErrNotSupported = errors.New("not supported by this source")
+
+// PriceSource is a marketplace feed of sales and listings. Fetches are paged:
+// an empty cursor requests the first page and each page returns the cursor of
+// the next, empty after the last.
+type PriceSource interface {
+	// Name is the marketplace stored as the source of ingested documents
+	Name() string
+
+	// Sales returns completed sales on or after since
+	Sales(ctx context.Context, cursor string, since time.Time) (Page[Sale], error)
+
+	// Listings returns the marketplace's current asks
+	Listings(ctx context.Context, cursor string) (Page[Listing], error)
+
+	// Product describes a marketplace product so it can be mapped to a card
+	Product(ctx context.Context, externalID string) (Product, error)
+}
+
+// Page is one page of a paged fetch
+type Page[T any] struct {
+	Items []T
+	Next  string // Cursor of the next page; empty after the last
+}
+
+// Printing identifies a card variant. Empty fields fall back to the
+// product's printing, then to the default variant's.
+type Printing struct {
+	Finish   string // One of variants.Finishes
+	Edition  string
+	Language string // ISO 639-1
+}
+
+// Sale is a completed sale as reported by a marketplace
+type Sale struct {
+	ID        string // Marketplace sale ID; derived from the sale's fields when empty
+	ProductID string // Marketplace product ID, mapped to a card
+	Printing  Printing
+	Price     float64
+	Currency  string // Empty means the marketplace's usual currency
+	Quantity  int
+	Condition string // Canonical condition; empty means Near Mint
+	Grader    string
+	Grade     float64
+	SoldAt    time.Time
+}
+
+// Listing is a current ask as reported by a marketplace
+type Listing struct {
+	ID        string // Marketplace listing ID
+	ProductID string
+	Printing  Printing
+	Title     string
+	Price     float64
+	Currency  string
+	Quantity  int
+	Condition string
+	Seller    string
+	Grader    string
+	Grade     float64
+	ImageURL  string
+	ListedAt  time.Time
+}
+
+// Product is a marketplace's catalog entry
+type Product struct {
+	ID       string
+	Name     string
+	Set      string
+	Number   string
+	Game     string
+	Printing Printing
+}
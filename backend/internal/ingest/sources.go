@@ -0,0 +1,55 @@
+package ingest
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// constructors creates each supported marketplace's source
+var constructors = map[string]func(Endpoint) PriceSource{
+	"ebay":       NewEBay,
+	"tcgplayer":  NewTCGplayer,
+	"cardmarket": NewCardmarket,
+}
+
+// Sources lists the supported marketplaces
+var Sources = []string{"ebay", "tcgplayer", "cardmarket"}
+
+// New creates the named marketplace's source
+func New(name string, endpoint Endpoint) (PriceSource, error) {
+	constructor, ok := constructors[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown source %q: must be one of %s", name, strings.Join(Sources, ", "))
+	}
+	return constructor(endpoint), nil
+}
+
+// Open creates the named marketplace's source. When fixturesDir is set it
+// reads recorded responses from fixturesDir/<name> instead of the endpoint.
+func Open(name string, endpoint Endpoint, fixturesDir string) (PriceSource, error) {
+	if fixturesDir != "" {
+		endpoint = Endpoint{
+			BaseURL: "http://" + name + ".fixtures",
+			Client:  &http.Client{Transport: FixtureTransport{Dir: filepath.Join(fixturesDir, name)}},
+		}
+	}
+	return New(name, endpoint)
+}
+
+// ParseSources parses a comma-separated list of marketplaces
+func ParseSources(raw string) ([]string, error) {
+	var names []string
+	for _, part := range strings.Split(raw, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		if _, ok := constructors[name]; !ok {
+			return nil, fmt.Errorf("unknown source %q: must be one of %s", name, strings.Join(Sources, ", "))
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
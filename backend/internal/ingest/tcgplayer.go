@@ -0,0 +1,182 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tcgplayerPageSize is the page size TCGplayer's APIs allow at most
+const tcgplayerPageSize = 100
+
+// tcgPlayer reads latest sales and marketplace listings per SKU, keyed by
+// TCGplayer product ID with the printing and language on each record
+type tcgPlayer struct {
+	client client
+}
+
+// NewTCGplayer creates a TCGplayer source
+func NewTCGplayer(endpoint Endpoint) PriceSource {
+	return &tcgPlayer{client: newClient(endpoint)}
+}
+
+func (t *tcgPlayer) Name() string { return "tcgplayer" }
+
+type tcgplayerSalesResponse struct {
+	TotalResults int `json:"totalResults"`
+	Results      []struct {
+		ProductID     int       `json:"productId"`
+		SkuID         int       `json:"skuId"`
+		Condition     string    `json:"condition"`
+		Printing      string    `json:"printing"`
+		Language      string    `json:"language"`
+		PurchasePrice float64   `json:"purchasePrice"`
+		Quantity      int       `json:"quantity"`
+		OrderDate     time.Time `json:"orderDate"`
+	} `json:"results"`
+}
+
+func (t *tcgPlayer) Sales(ctx context.Context, cursor string, since time.Time) (Page[Sale], error) {
+	query, offset := tcgplayerQuery(cursor)
+	query.Set("since", since.UTC().Format(time.RFC3339))
+
+	var resp tcgplayerSalesResponse
+	if err := t.client.get(ctx, "/v2/sales", query, &resp); err != nil {
+		return Page[Sale]{}, err
+	}
+
+	page := Page[Sale]{Next: tcgplayerNext(offset, len(resp.Results), resp.TotalResults)}
+	for _, r := range resp.Results {
+		if r.ProductID == 0 {
+			continue
+		}
+		page.Items = append(page.Items, Sale{
+			// Latest sales carry no ID; the runner derives one from the fields
+			ProductID: strconv.Itoa(r.ProductID),
+			Printing:  tcgplayerPrinting(r.Printing, r.Language),
+			Price:     r.PurchasePrice,
+			Currency:  "USD",
+			Quantity:  r.Quantity,
+			Condition: conditionOf(r.Condition),
+			SoldAt:    r.OrderDate,
+		})
+	}
+	return page, nil
+}
+
+type tcgplayerListingsResponse struct {
+	TotalResults int `json:"totalResults"`
+	Results      []struct {
+		ListingID  string    `json:"listingId"`
+		ProductID  int       `json:"productId"`
+		Condition  string    `json:"condition"`
+		Printing   string    `json:"printing"`
+		Language   string    `json:"language"`
+		Price      float64   `json:"price"`
+		Quantity   int       `json:"quantity"`
+		SellerName string    `json:"sellerName"`
+		ListedAt   time.Time `json:"listedAt"`
+	} `json:"results"`
+}
+
+func (t *tcgPlayer) Listings(ctx context.Context, cursor string) (Page[Listing], error) {
+	query, offset := tcgplayerQuery(cursor)
+
+	var resp tcgplayerListingsResponse
+	if err := t.client.get(ctx, "/v2/listings", query, &resp); err != nil {
+		return Page[Listing]{}, err
+	}
+
+	page := Page[Listing]{Next: tcgplayerNext(offset, len(resp.Results), resp.TotalResults)}
+	for _, r := range resp.Results {
+		if r.ListingID == "" || r.ProductID == 0 {
+			continue
+		}
+		page.Items = append(page.Items, Listing{
+			ID:        r.ListingID,
+			ProductID: strconv.Itoa(r.ProductID),
+			Printing:  tcgplayerPrinting(r.Printing, r.Language),
+			Price:     r.Price,
+			Currency:  "USD",
+			Quantity:  r.Quantity,
+			Condition: conditionOf(r.Condition),
+			Seller:    r.SellerName,
+			ListedAt:  r.ListedAt,
+		})
+	}
+	return page, nil
+}
+
+type tcgplayerProductResponse struct {
+	Success bool `json:"success"`
+	Results []struct {
+		ProductID    int    `json:"productId"`
+		Name         string `json:"name"`
+		GroupName    string `json:"groupName"`
+		CategoryName string `json:"categoryName"`
+		ExtendedData []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"extendedData"`
+	} `json:"results"`
+}
+
+func (t *tcgPlayer) Product(ctx context.Context, externalID string) (Product, error) {
+	var resp tcgplayerProductResponse
+	if err := t.client.get(ctx, "/catalog/products/"+url.PathEscape(externalID), nil, &resp); err != nil {
+		return Product{}, err
+	}
+	if len(resp.Results) == 0 {
+		return Product{}, fmt.Errorf("tcgplayer product %s not found", externalID)
+	}
+
+	r := resp.Results[0]
+	product := Product{
+		ID:   strconv.Itoa(r.ProductID),
+		Name: r.Name,
+		Set:  r.GroupName,
+		Game: gameName(r.CategoryName),
+	}
+	for _, d := range r.ExtendedData {
+		if d.Name == "Number" {
+			product.Number = d.Value
+		}
+	}
+	return product, nil
+}
+
+// tcgplayerQuery returns the paging parameters for an offset cursor and the offset
+func tcgplayerQuery(cursor string) (url.Values, int) {
+	offset, _ := strconv.Atoi(cursor)
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(tcgplayerPageSize))
+	if offset > 0 {
+		query.Set("offset", strconv.Itoa(offset))
+	}
+	return query, offset
+}
+
+// tcgplayerNext returns the offset of the next page, empty after the last
+func tcgplayerNext(offset, count, total int) string {
+	if count == 0 || offset+count >= total {
+		return ""
+	}
+	return strconv.Itoa(offset + count)
+}
+
+// tcgplayerPrinting maps a SKU's printing, e.g. "1st Edition Holofoil", and language
+func tcgplayerPrinting(printing, language string) Printing {
+	p := Printing{Language: languageOf(language)}
+	if rest, ok := strings.CutPrefix(strings.TrimSpace(printing), "1st Edition"); ok {
+		p.Edition = "1st_edition"
+		printing = rest
+		if strings.TrimSpace(printing) == "" {
+			printing = "Normal"
+		}
+	}
+	p.Finish = finishOf(printing)
+	return p
+}
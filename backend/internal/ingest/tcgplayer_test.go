@@ -0,0 +1,108 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jamesc159/monmetrics/internal/conditions"
+)
+
+func TestTCGplayerSales(t *testing.T) {
+	server, requests := fixtureServer(t, "tcgplayer")
+	source := NewTCGplayer(Endpoint{BaseURL: server.URL})
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	page, err := source.Sales(context.Background(), "", since)
+	if err != nil {
+		t.Fatalf("Sales: %v", err)
+	}
+	if page.Next != "4" || len(page.Items) != 4 {
+		t.Fatalf("got %d sales, next %q; want 4 and next 4", len(page.Items), page.Next)
+	}
+	if got := (*requests)[0].URL.Query().Get("since"); got != "2026-10-01T00:00:00Z" {
+		t.Errorf("since = %q", got)
+	}
+
+	first := page.Items[0]
+	if first.ID != "" {
+		t.Errorf("ID = %q, want none so the runner derives one", first.ID)
+	}
+	if first.ProductID != "226584" || first.Price != 72.49 || first.Currency != "USD" {
+		t.Errorf("unexpected first sale: %+v", first)
+	}
+	if first.Printing != (Printing{Finish: "foil", Language: "en"}) {
+		t.Errorf("Printing = %+v, want English foil", first.Printing)
+	}
+	if got := page.Items[2].Condition; got != conditions.LightlyPlayed {
+		t.Errorf("Condition = %q, want Lightly Played", got)
+	}
+
+	last, err := source.Sales(context.Background(), page.Next, since)
+	if err != nil {
+		t.Fatalf("Sales page 2: %v", err)
+	}
+	if last.Next != "" || len(last.Items) != 3 {
+		t.Fatalf("got %d sales, next %q; want 3 and no next", len(last.Items), last.Next)
+	}
+	if got := last.Items[0].Printing; got != (Printing{Finish: "normal", Edition: "1st_edition", Language: "en"}) {
+		t.Errorf("1st Edition printing = %+v", got)
+	}
+	if got := last.Items[1].Quantity; got != 3 {
+		t.Errorf("Quantity = %d, want 3", got)
+	}
+}
+
+func TestTCGplayerListings(t *testing.T) {
+	server, _ := fixtureServer(t, "tcgplayer")
+	source := NewTCGplayer(Endpoint{BaseURL: server.URL})
+
+	page, err := source.Listings(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Listings: %v", err)
+	}
+	if page.Next != "" || len(page.Items) != 4 {
+		t.Fatalf("got %d listings, next %q; want 4 and no next", len(page.Items), page.Next)
+	}
+
+	second := page.Items[1]
+	if second.ID != "7f3c2a10-0002" || second.Seller != "Poke Depot" || second.Quantity != 2 || second.Condition != conditions.LightlyPlayed {
+		t.Errorf("unexpected listing: %+v", second)
+	}
+}
+
+func TestTCGplayerProduct(t *testing.T) {
+	server, _ := fixtureServer(t, "tcgplayer")
+	source := NewTCGplayer(Endpoint{BaseURL: server.URL})
+
+	product, err := source.Product(context.Background(), "490017")
+	if err != nil {
+		t.Fatalf("Product: %v", err)
+	}
+	want := Product{
+		ID:     "490017",
+		Name:   "Ragavan, Nimble Pilferer",
+		Set:    "Modern Horizons 2",
+		Number: "138/303",
+		Game:   "Magic The Gathering",
+	}
+	if product != want {
+		t.Errorf("Product = %+v, want %+v", product, want)
+	}
+}
+
+func TestTCGplayerNext(t *testing.T) {
+	tests := []struct {
+		offset, count, total int
+		want                 string
+	}{
+		{0, 100, 250, "100"},
+		{200, 50, 250, ""},
+		{0, 0, 10, ""},
+	}
+	for _, tt := range tests {
+		if got := tcgplayerNext(tt.offset, tt.count, tt.total); got != tt.want {
+			t.Errorf("tcgplayerNext(%d, %d, %d) = %q, want %q", tt.offset, tt.count, tt.total, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package liquidity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+const (
+	// DefaultWindowDays is the sales lookback used for the metrics
+	DefaultWindowDays = 30
+
+	// Score weights; they sum to 1 so the score spans 0-100
+	sellThroughWeight = 0.5
+	velocityWeight    = 0.3
+	speedWeight       = 0.2
+
+	// saturatingDailySales is the sales rate at which velocity maxes out
+	saturatingDailySales = 5
+	// halfSpeedDays is the days-to-sell at which the speed component halves
+	halfSpeedDays = 30
+)
+
+// Compute derives liquidity metrics from units sold in the window and units
+// currently listed. Sell-through is sold / (sold + listed). Days-to-sell is how
+// long a newly listed copy would take to sell behind the existing supply at the
+// recent sales rate; it is nil when nothing sold.
+func Compute(sold, listed, windowDays int, now time.Time) models.Liquidity {
+	result := models.Liquidity{
+		UnitsSold:      sold,
+		ActiveListings: listed,
+		WindowDays:     windowDays,
+		ComputedAt:     now,
+	}
+	if sold+listed > 0 {
+		result.SellThroughRate = round(float64(sold)/float64(sold+listed), 4)
+	}
+
+	speed := 0.0
+	dailySales := float64(sold) / float64(windowDays)
+	if sold > 0 {
+		days := round(float64(listed+1)/dailySales, 1)
+		result.DaysToSell = &days
+		speed = halfSpeedDays / (halfSpeedDays + days)
+	}
+
+	velocity := math.Min(1, math.Log1p(dailySales)/math.Log1p(saturatingDailySales))
+	score := 100 * (sellThroughWeight*result.SellThroughRate + velocityWeight*velocity + speedWeight*speed)
+	result.Score = int(math.Round(score))
+	return result
+}
+
+// Calculator computes and stores liquidity metrics on cards
+type Calculator struct {
+	db         *mongo.Database
+	windowDays int
+}
+
+// Result summarizes a liquidity run
+type Result struct {
+	Cards int `json:"cards"`
+}
+
+// New creates a new Calculator instance
+func New(db *mongo.Database, windowDays int) *Calculator {
+	if windowDays <= 0 {
+		windowDays = DefaultWindowDays
+	}
+	return &Calculator{db: db, windowDays: windowDays}
+}
+
+// ForCard computes a single card's metrics without storing them
+func (c *Calculator) ForCard(ctx context.Context, cardID primitive.ObjectID) (models.Liquidity, error) {
+	now := time.Now().UTC()
+	sold, listed, err := c.counts(ctx, now, bson.M{"card_id": cardID})
+	if err != nil {
+		return models.Liquidity{}, err
+	}
+	return Compute(sold[cardID], listed[cardID], c.windowDays, now), nil
+}
+
+// Run recomputes every card's metrics and writes them to cards.liquidity so
+// search can filter and sort on them
+func (c *Calculator) Run(ctx context.Context) (Result, error) {
+	var result Result
+	now := time.Now().UTC()
+
+	sold, listed, err := c.counts(ctx, now, bson.M{})
+	if err != nil {
+		return result, err
+	}
+
+	ids, err := c.db.Collection("cards").Distinct(ctx, "_id", bson.M{})
+	if err != nil {
+		return result, fmt.Errorf("failed to list cards: %v", err)
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(ids))
+	for _, v := range ids {
+		cardID, ok := v.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		metrics := Compute(sold[cardID], listed[cardID], c.windowDays, now)
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": cardID}).
+			SetUpdate(bson.M{"$set": bson.M{"liquidity": metrics}}))
+	}
+
+	if len(writes) > 0 {
+		if _, err := c.db.Collection("cards").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return result, fmt.Errorf("failed to write liquidity metrics: %v", err)
+		}
+	}
+	result.Cards = len(writes)
+
+	return result, nil
+}
+
+// counts returns units sold in the window (outliers excluded) and units
+// currently listed, per card, for cards matching match
+func (c *Calculator) counts(ctx context.Context, now time.Time, match bson.M) (sold, listed map[primitive.ObjectID]int, err error) {
+	salesMatch := bson.M{
+		"timestamp": bson.M{"$gte": now.AddDate(0, 0, -c.windowDays)},
+		"outlier":   bson.M{"$ne": true},
+	}
+	for k, v := range match {
+		salesMatch[k] = v
+	}
+
+	// Unreported volume or quantity still counts as one unit
+	sold, err = c.sumBy(ctx, "prices", salesMatch, bson.M{"$max": bson.A{"$volume", 1}})
+	if err != nil {
+		return nil, nil, err
+	}
+	listed, err = c.sumBy(ctx, "listings", match, bson.M{"$max": bson.A{"$quantity", 1}})
+	if err != nil {
+		return nil, nil, err
+	}
+	return sold, listed, nil
+}
+
+// sumBy totals value per card_id over documents in collection matching filter
+func (c *Calculator) sumBy(ctx context.Context, collection string, filter bson.M, value interface{}) (map[primitive.ObjectID]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{"_id": "$card_id", "total": bson.M{"$sum": value}}}},
+	}
+
+	cursor, err := c.db.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate %s: %v", collection, err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		CardID primitive.ObjectID `bson:"_id"`
+		Total  int                `bson:"total"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode %s totals: %v", collection, err)
+	}
+
+	totals := make(map[primitive.ObjectID]int, len(rows))
+	for _, row := range rows {
+		totals[row.CardID] = row.Total
+	}
+	return totals, nil
+}
+
+// round rounds v to the given number of decimal places
+func round(v float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(v*p) / p
+}
@@ -57,6 +57,51 @@ func AuthRequired(jwtSecret []byte) Middleware {
 	}
 }
 
+// OptionalAuth attaches JWT claims to the request context when a valid bearer
+// token is present, but lets anonymous requests through unchanged
+func OptionalAuth(jwtSecret []byte) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+			if authHeader == "" || tokenString == authHeader {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, err := validateJWT(tokenString, jwtSecret)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), ClaimsKey, claims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// AdminKey middleware for admin routes: requests must carry the configured
+// key in the X-Admin-Key header. An empty key disables admin routes entirely.
+func AdminKey(key string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key == "" {
+				http.Error(w, "Admin API disabled", http.StatusForbidden)
+				return
+			}
+
+			provided := r.Header.Get("X-Admin-Key")
+			if provided == "" || !hmac.Equal([]byte(provided), []byte(key)) {
+				http.Error(w, "Invalid admin key", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // validateJWT validates a JWT token using HMAC-SHA256
 func validateJWT(tokenString string, secret []byte) (*Claims, error) {
 	parts := strings.Split(tokenString, ".")
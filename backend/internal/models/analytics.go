@@ -0,0 +1,111 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SpreadPoint represents the cross-marketplace price spread for a single day
+type SpreadPoint struct {
+	Date       time.Time          `json:"date"`
+	Prices     map[string]float64 `json:"prices"`     // Average price per source
+	Spread     float64            `json:"spread"`     // Highest minus lowest source price
+	SpreadPct  float64            `json:"spread_pct"` // Spread as a percentage of the lowest price
+	HighSource string             `json:"high_source"`
+	LowSource  string             `json:"low_source"`
+}
+
+// SpreadAnalysis represents daily spreads between sources and their summary statistics
+type SpreadAnalysis struct {
+	CardID           primitive.ObjectID `json:"card_id"`
+	TimeRange        string             `json:"time_range"`
+	Sources          []string           `json:"sources"`
+	Days             []SpreadPoint      `json:"days"`
+	MeanSpread       float64            `json:"mean_spread"`
+	MeanSpreadPct    float64            `json:"mean_spread_pct"`
+	SpreadVolatility float64            `json:"spread_volatility"` // Std dev of daily spread_pct
+}
+
+// Drawdown represents a peak-to-trough decline in a price series
+type Drawdown struct {
+	Pct         float64   `json:"pct"` // Negative percentage from peak to trough
+	PeakDate    time.Time `json:"peak_date"`
+	PeakPrice   float64   `json:"peak_price"`
+	TroughDate  time.Time `json:"trough_date"`
+	TroughPrice float64   `json:"trough_price"`
+	Recovered   bool      `json:"recovered"`
+}
+
+// CardStats represents risk and return statistics for a card
+type CardStats struct {
+	CardID               primitive.ObjectID  `json:"card_id"`
+	TimeRange            string              `json:"time_range"`
+	From                 time.Time           `json:"from"`
+	To                   time.Time           `json:"to"`
+	Observations         int                 `json:"observations"` // Daily closes in range
+	CurrentPrice         float64             `json:"current_price"`
+	AnnualizedVolatility float64             `json:"annualized_volatility"` // Percent
+	AnnualizedReturn     float64             `json:"annualized_return"`     // Percent, from mean log return
+	SharpeRatio          float64             `json:"sharpe_ratio"`          // Zero risk-free rate
+	SortinoRatio         float64             `json:"sortino_ratio"`
+	MaxDrawdown          Drawdown            `json:"max_drawdown"`
+	Returns              map[string]*float64 `json:"returns"` // "1d", "7d", "30d", "90d", "1y"; null when history is too short
+	AllTimeHigh          float64             `json:"all_time_high"`
+	AllTimeLow           float64             `json:"all_time_low"`
+	DistanceFromATH      float64             `json:"distance_from_ath_pct"`
+	DistanceFromATL      float64             `json:"distance_from_atl_pct"`
+}
+
+// CorrelatedCard represents another card's return correlation with a target card
+type CorrelatedCard struct {
+	CardID       primitive.ObjectID `json:"card_id"`
+	Name         string             `json:"name"`
+	Set          string             `json:"set"`
+	Game         string             `json:"game"`
+	ImageURL     string             `json:"image_url"`
+	Correlation  float64            `json:"correlation"`
+	Observations int                `json:"observations"` // Aligned daily returns
+}
+
+// CorrelationAnalysis represents a card's most/least correlated cards and its market beta
+type CorrelationAnalysis struct {
+	CardID           primitive.ObjectID `json:"card_id"`
+	TimeRange        string             `json:"time_range"`
+	From             time.Time          `json:"from"`
+	To               time.Time          `json:"to"`
+	Market           string             `json:"market"` // Game the beta is measured against
+	Beta             *float64           `json:"beta"`   // null when history is too short
+	BetaObservations int                `json:"beta_observations"`
+	MostCorrelated   []CorrelatedCard   `json:"most_correlated"`
+	LeastCorrelated  []CorrelatedCard   `json:"least_correlated"`
+	ComputedAt       time.Time          `json:"computed_at"`
+}
+
+// ForecastPoint represents a projected price with its confidence band
+type ForecastPoint struct {
+	Date  time.Time `json:"date"`
+	Value float64   `json:"value"`
+	Lower float64   `json:"lower"`
+	Upper float64   `json:"upper"`
+}
+
+// ForecastAccuracy represents the error of the model on recently held-out data
+type ForecastAccuracy struct {
+	HoldoutDays int     `json:"holdout_days"`
+	MAPE        float64 `json:"mape"` // Mean absolute percentage error
+	RMSE        float64 `json:"rmse"` // Root mean squared error, in price units
+}
+
+// Forecast represents a price forecast for a card
+type Forecast struct {
+	CardID       primitive.ObjectID `json:"card_id"`
+	Model        string             `json:"model"` // "ses", "holt", "holt_winters", "linear"
+	HorizonDays  int                `json:"horizon_days"`
+	Confidence   float64            `json:"confidence"`   // Band coverage, e.g. 0.95
+	Observations int                `json:"observations"` // Daily closes the model was fitted on
+	Parameters   map[string]float64 `json:"parameters"`
+	Points       []ForecastPoint    `json:"points"`
+	Backtest     *ForecastAccuracy  `json:"backtest"` // null when history is too short to hold out
+	GeneratedAt  time.Time          `json:"generated_at"`
+}
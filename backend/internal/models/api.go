@@ -27,6 +27,15 @@ type SearchParams struct {
 	Category string `json:"category,omitempty"`
 	Page     int    `json:"page,omitempty"`
 	Limit    int    `json:"limit,omitempty"`
+
+	// Liquidity filters and sort ("updated", "liquidity", "sell_through", "days_to_sell", "fair_value")
+	MinLiquidity   int     `json:"min_liquidity,omitempty"`
+	MinSellThrough float64 `json:"min_sell_through,omitempty"`
+	MaxDaysToSell  float64 `json:"max_days_to_sell,omitempty"`
+	Sort           string  `json:"sort,omitempty"`
+
+	// Currency to convert prices to (USD, EUR, GBP, JPY)
+	Currency string `json:"currency,omitempty"`
 }
 
 // ═══════════════════════════════════════════════════════════════════════════════
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+)
+
+// BacktestRule is a single condition over an indicator series, e.g. rsi_14 < 30
+type BacktestRule struct {
+	Indicator string   `json:"indicator"`            // Series key such as "rsi_14", "bollinger_20_2_lower" or "price"
+	Operator  string   `json:"operator"`             // "<", "<=", ">", ">=", "crosses_above", "crosses_below"
+	Value     *float64 `json:"value,omitempty"`      // Constant to compare against
+	CompareTo string   `json:"compare_to,omitempty"` // Or another series key, e.g. "sma_50"
+}
+
+// BacktestRequest represents a strategy to simulate over a saved chart
+type BacktestRequest struct {
+	Entry          []BacktestRule `json:"entry"`                // All rules must hold to buy
+	Exit           []BacktestRule `json:"exit"`                 // All rules must hold to sell
+	TimeRange      string         `json:"time_range,omitempty"` // Defaults to the chart's time range
+	InitialCapital float64        `json:"initial_capital,omitempty"`
+	BuyFeePct      float64        `json:"buy_fee_pct,omitempty"`  // Percent of purchase price
+	SellFeePct     float64        `json:"sell_fee_pct,omitempty"` // Marketplace fee, percent of sale price
+	FixedFee       float64        `json:"fixed_fee,omitempty"`    // Flat fee per trade side (e.g. shipping)
+}
+
+// BacktestTrade represents one completed round trip
+type BacktestTrade struct {
+	EntryDate   time.Time `json:"entry_date"`
+	EntryPrice  float64   `json:"entry_price"`
+	ExitDate    time.Time `json:"exit_date"`
+	ExitPrice   float64   `json:"exit_price"`
+	Fees        float64   `json:"fees"`
+	ProfitLoss  float64   `json:"profit_loss"`
+	ReturnPct   float64   `json:"return_pct"`
+	ClosedAtEnd bool      `json:"closed_at_end"` // Position still open when the data ran out
+	HoldingDays int       `json:"holding_days"`
+}
+
+// EquityPoint represents portfolio value on a given day
+type EquityPoint struct {
+	Date   time.Time `json:"date"`
+	Equity float64   `json:"equity"`
+}
+
+// BacktestResult represents the outcome of a strategy simulation
+type BacktestResult struct {
+	ChartID        string          `json:"chart_id"`
+	CardID         string          `json:"card_id"`
+	TimeRange      string          `json:"time_range"`
+	InitialCapital float64         `json:"initial_capital"`
+	FinalEquity    float64         `json:"final_equity"`
+	TotalReturnPct float64         `json:"total_return_pct"`
+	BuyAndHoldPct  float64         `json:"buy_and_hold_pct"`
+	TotalTrades    int             `json:"total_trades"`
+	WinRate        float64         `json:"win_rate"` // Percent of trades with positive P&L
+	TotalFees      float64         `json:"total_fees"`
+	MaxDrawdown    Drawdown        `json:"max_drawdown"`
+	Trades         []BacktestTrade `json:"trades"`
+	EquityCurve    []EquityPoint   `json:"equity_curve"`
+}
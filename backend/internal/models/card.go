@@ -26,6 +26,7 @@ type Card struct {
 	AllTimeLow   float64   `bson:"all_time_low" json:"all_time_low"`
 	ATHDate      time.Time `bson:"ath_date" json:"ath_date"`
 	ATLDate      time.Time `bson:"atl_date" json:"atl_date"`
+	Currency     string    `bson:"-" json:"currency,omitempty"` // Set when prices were converted from USD
 
 	// Search and categorization
 	SearchTerms []string `bson:"search_terms" json:"search_terms"`
@@ -33,6 +34,71 @@ type Card struct {
 
 	// Popularity and ranking (based on 6-month metrics)
 	PopularityRank int `bson:"popularity_rank,omitempty" json:"popularity_rank,omitempty"`
+
+	// How easily the card sells at market (recomputed periodically)
+	Liquidity *Liquidity `bson:"liquidity,omitempty" json:"liquidity,omitempty"`
+
+	// What a Near Mint raw copy is worth, blending sales and asks (recomputed periodically)
+	FairValue *FairValue `bson:"fair_value,omitempty" json:"fair_value,omitempty"`
+
+	// Packs and pull rates of a sealed product, used for expected value
+	Contents *SealedContents `bson:"contents,omitempty" json:"contents,omitempty"`
+
+	// Printings of this card; prices, listings and charts reference one by ID
+	Variants []CardVariant `bson:"variants,omitempty" json:"variants,omitempty"`
+}
+
+// CardVariant represents one printing of a card
+type CardVariant struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	Finish    string             `bson:"finish" json:"finish"`                       // "normal", "foil", "reverse_holo", "etched"
+	Edition   string             `bson:"edition,omitempty" json:"edition,omitempty"` // "1st_edition", "unlimited", "shadowless", etc.
+	Language  string             `bson:"language" json:"language"`                   // ISO 639-1, e.g. "en", "ja"
+	Promo     string             `bson:"promo,omitempty" json:"promo,omitempty"`     // Promo stamp, e.g. "prerelease"
+	IsDefault bool               `bson:"is_default" json:"is_default"`               // Holds data recorded before variants existed
+}
+
+// Liquidity represents how quickly a card sells relative to its supply
+type Liquidity struct {
+	SellThroughRate float64   `bson:"sell_through_rate" json:"sell_through_rate"` // Sold / (sold + listed), 0-1
+	DaysToSell      *float64  `bson:"days_to_sell" json:"days_to_sell"`           // Null when nothing sold in the window
+	Score           int       `bson:"score" json:"score"`                         // 0-100, higher sells faster
+	UnitsSold       int       `bson:"units_sold" json:"units_sold"`
+	ActiveListings  int       `bson:"active_listings" json:"active_listings"` // Units currently listed
+	WindowDays      int       `bson:"window_days" json:"window_days"`
+	ComputedAt      time.Time `bson:"computed_at" json:"computed_at"`
+}
+
+// FairValue represents a card's estimated worth from recent sales, capped by
+// the cheapest active listings
+type FairValue struct {
+	Value      float64              `bson:"value" json:"value"`                         // Near Mint point estimate
+	SalesValue float64              `bson:"sales_value" json:"sales_value"`             // Estimate from sales alone, before the listing ceiling
+	Low        float64              `bson:"low" json:"low"`                             // Weighted 20th percentile of recent sales
+	High       float64              `bson:"high" json:"high"`                           // Weighted 80th percentile of recent sales
+	Confidence float64              `bson:"confidence" json:"confidence"`               // 0-1 from sample size, dispersion and recency
+	Sales      int                  `bson:"sales" json:"sales"`                         // Sales in the window the estimate is based on
+	Ceiling    *float64             `bson:"ceiling,omitempty" json:"ceiling,omitempty"` // Cheapest ask in Near Mint terms; null without listings
+	Capped     bool                 `bson:"capped" json:"capped"`                       // True when the ceiling lowered the estimate
+	Conditions []FairValueCondition `bson:"conditions" json:"conditions"`
+	Currency   string               `bson:"-" json:"currency,omitempty"` // Set when values were converted from USD
+	WindowDays int                  `bson:"window_days" json:"window_days"`
+	ComputedAt time.Time            `bson:"computed_at" json:"computed_at"`
+}
+
+// FairValueCondition represents the fair value of a copy in one condition
+type FairValueCondition struct {
+	Condition string   `bson:"condition" json:"condition"`
+	Value     float64  `bson:"value" json:"value"`
+	LowestAsk *float64 `bson:"lowest_ask,omitempty" json:"lowest_ask,omitempty"`
+	Capped    bool     `bson:"capped" json:"capped"` // True when the lowest ask in this condition is below the estimate
+}
+
+// CardView represents a card's page views on one day
+type CardView struct {
+	CardID primitive.ObjectID `bson:"card_id" json:"card_id"`
+	Date   time.Time          `bson:"date" json:"date"` // UTC midnight
+	Count  int                `bson:"count" json:"count"`
 }
 
 // SearchResult represents search results for cards
@@ -68,4 +134,5 @@ type FeaturedContent struct {
 	// Market mover specific fields
 	PriceChange      float64 `bson:"price_change,omitempty" json:"price_change,omitempty"`             // Percentage
 	PriceChangeValue float64 `bson:"price_change_value,omitempty" json:"price_change_value,omitempty"` // Dollar amount
+	Window           string  `bson:"window,omitempty" json:"window,omitempty"`                         // Lookback of computed movers, e.g. "7d"
 }
@@ -8,15 +8,16 @@ import (
 
 // SavedChart represents a user's saved chart configuration
 type SavedChart struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
-	CardID      primitive.ObjectID `bson:"card_id" json:"card_id"`
-	Name        string             `bson:"name" json:"name"`
-	Description string             `bson:"description,omitempty" json:"description,omitempty"`
-	Indicators  []ChartIndicator   `bson:"indicators" json:"indicators"`
-	TimeRange   string             `bson:"time_range" json:"time_range"` // "1d", "7d", "30d", "90d", "1y", "5y"
-	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID  `bson:"user_id" json:"user_id"`
+	CardID      primitive.ObjectID  `bson:"card_id" json:"card_id"`
+	VariantID   *primitive.ObjectID `bson:"variant_id,omitempty" json:"variant_id,omitempty"` // Omitted for the card's default variant
+	Name        string              `bson:"name" json:"name"`
+	Description string              `bson:"description,omitempty" json:"description,omitempty"`
+	Indicators  []ChartIndicator    `bson:"indicators" json:"indicators"`
+	TimeRange   string              `bson:"time_range" json:"time_range"` // "1d", "7d", "30d", "90d", "1y", "5y", "ytd", "max"
+	CreatedAt   time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time           `bson:"updated_at" json:"updated_at"`
 }
 
 // ChartIndicator represents a technical indicator configuration
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GradeRung represents recent sales of one grader's grade
+type GradeRung struct {
+	Grader      string    `json:"grader"`
+	Grade       float64   `json:"grade"`
+	MedianPrice float64   `json:"median_price"`
+	LastPrice   float64   `json:"last_price"`
+	LastSale    time.Time `json:"last_sale"`
+	Sales       int       `json:"sales"`
+	Multiple    float64   `json:"multiple,omitempty"` // Median vs. raw median
+}
+
+// GradeLadder represents a card's prices across graders and grades
+type GradeLadder struct {
+	CardID    primitive.ObjectID `json:"card_id"`
+	TimeRange string             `json:"time_range"`
+	From      time.Time          `json:"from"`
+	To        time.Time          `json:"to"`
+	RawPrice  float64            `json:"raw_price"` // Median ungraded sale
+	Rungs     []GradeRung        `json:"rungs"`
+}
+
+// GradeOutcome represents one possible grade in a grading ROI estimate
+type GradeOutcome struct {
+	Grade       float64 `json:"grade,omitempty"` // Omitted for the unassigned remainder
+	Probability float64 `json:"probability"`
+	Value       float64 `json:"value"`
+	Basis       string  `json:"basis"` // "observed" (grade's median sale) or "raw" (no graded sales)
+}
+
+// GradingROI represents the expected return of grading a raw copy
+type GradingROI struct {
+	CardID         primitive.ObjectID `json:"card_id"`
+	Grader         string             `json:"grader"`
+	RawPrice       float64            `json:"raw_price"`
+	GradingFee     float64            `json:"grading_fee"`
+	Cost           float64            `json:"cost"` // Raw price plus grading fee
+	ExpectedValue  float64            `json:"expected_value"`
+	ExpectedProfit float64            `json:"expected_profit"`
+	ROI            float64            `json:"roi"` // Percentage of cost
+	Outcomes       []GradeOutcome     `json:"outcomes"`
+}
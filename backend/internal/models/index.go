@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MarketIndex represents a benchmark index built from a slice of the card catalog
+type MarketIndex struct {
+	ID             primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Slug           string               `bson:"slug" json:"slug"` // e.g. "pokemon-singles"
+	Name           string               `bson:"name" json:"name"` // e.g. "Pokemon Singles Index"
+	Game           string               `bson:"game,omitempty" json:"game,omitempty"`
+	Set            string               `bson:"set,omitempty" json:"set,omitempty"`
+	Category       string               `bson:"category,omitempty" json:"category,omitempty"` // "card" or "sealed"
+	Weighting      string               `bson:"weighting" json:"weighting"`                   // "price" or "equal"
+	Rebalance      string               `bson:"rebalance" json:"rebalance"`                   // "daily", "weekly", "monthly"
+	BaseValue      float64              `bson:"base_value" json:"base_value"`
+	Constituents   []primitive.ObjectID `bson:"constituents" json:"constituents"`
+	CurrentValue   float64              `bson:"current_value" json:"current_value"`
+	ChangePct      float64              `bson:"change_pct" json:"change_pct"` // Change vs previous day
+	LastComputedAt *time.Time           `bson:"last_computed_at,omitempty" json:"last_computed_at,omitempty"`
+	CreatedAt      time.Time            `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time            `bson:"updated_at" json:"updated_at"`
+}
+
+// IndexValue represents the daily level of a market index
+type IndexValue struct {
+	IndexID      primitive.ObjectID `bson:"index_id" json:"index_id"`
+	Date         time.Time          `bson:"date" json:"date"`
+	Value        float64            `bson:"value" json:"value"`
+	Constituents int                `bson:"constituents" json:"constituents"` // Cards priced that day
+}
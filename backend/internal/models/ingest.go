@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IngestCheckpoint records how far a marketplace feed has been read so an
+// interrupted run resumes from its last stored page
+type IngestCheckpoint struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Source      string             `bson:"source" json:"source"`                     // "ebay", "tcgplayer", "cardmarket"
+	Kind        string             `bson:"kind" json:"kind"`                         // "sales" or "listings"
+	Cursor      string             `bson:"cursor,omitempty" json:"cursor,omitempty"` // Next page of an unfinished pass; empty when the last pass completed
+	Since       time.Time          `bson:"since" json:"since"`                       // Sales: lower bound the current pass fetches from
+	Newest      time.Time          `bson:"newest" json:"newest"`                     // Sales: latest sale seen, the next pass's lower bound
+	PassStarted time.Time          `bson:"pass_started" json:"pass_started"`         // Listings: asks not seen since are removed when the pass completes
+	Pages       int                `bson:"pages" json:"pages"`                       // Pages read in the current pass
+	CompletedAt time.Time          `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// ExternalProduct maps a marketplace's product ID to a card. Mappings are
+// matched by name, set and number on first sight and can be corrected by
+// setting CardID and Manual.
+type ExternalProduct struct {
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Source     string              `bson:"source" json:"source"`
+	ExternalID string              `bson:"external_id" json:"external_id"`
+	CardID     *primitive.ObjectID `bson:"card_id" json:"card_id"` // Null when no card matched
+	Name       string              `bson:"name" json:"name"`
+	Set        string              `bson:"set" json:"set"`
+	Number     string              `bson:"number,omitempty" json:"number,omitempty"`
+	Game       string              `bson:"game,omitempty" json:"game,omitempty"`
+	Finish     string              `bson:"finish,omitempty" json:"finish,omitempty"` // Printing the product covers, when the marketplace lists printings separately
+	Edition    string              `bson:"edition,omitempty" json:"edition,omitempty"`
+	Language   string              `bson:"language,omitempty" json:"language,omitempty"`
+	Manual     bool                `bson:"manual,omitempty" json:"manual,omitempty"` // Set by hand; never re-matched
+	UpdatedAt  time.Time           `bson:"updated_at" json:"updated_at"`
+}
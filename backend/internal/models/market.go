@@ -20,15 +20,73 @@ type MarketData struct {
 
 // Listing represents a current marketplace listing
 type Listing struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	CardID    primitive.ObjectID `bson:"card_id" json:"card_id"`
-	Title     string             `bson:"title" json:"title"`
-	Price     float64            `bson:"price" json:"price"`
-	Quantity  int                `bson:"quantity" json:"quantity"`
-	Condition string             `bson:"condition" json:"condition"`
-	Seller    string             `bson:"seller" json:"seller"`
-	Source    string             `bson:"source" json:"source"` // "ebay", "tcgplayer"
-	ImageURL  string             `bson:"image_url,omitempty" json:"image_url,omitempty"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	CardID     primitive.ObjectID  `bson:"card_id" json:"card_id"`
+	VariantID  *primitive.ObjectID `bson:"variant_id,omitempty" json:"variant_id,omitempty"`
+	Title      string              `bson:"title" json:"title"`
+	Price      float64             `bson:"price" json:"price"`
+	Currency   string              `bson:"currency,omitempty" json:"currency,omitempty"` // ISO 4217; empty means USD
+	Quantity   int                 `bson:"quantity" json:"quantity"`
+	Condition  string              `bson:"condition" json:"condition"`
+	Seller     string              `bson:"seller" json:"seller"`
+	Source     string              `bson:"source" json:"source"`                               // "ebay", "tcgplayer"
+	ExternalID string              `bson:"external_id,omitempty" json:"external_id,omitempty"` // Marketplace listing ID of ingested asks, unique per source
+	Grader     string              `bson:"grader,omitempty" json:"grader,omitempty"`           // "PSA", "BGS", "CGC"; empty for raw copies
+	Grade      float64             `bson:"grade,omitempty" json:"grade,omitempty"`
+	ImageURL   string              `bson:"image_url,omitempty" json:"image_url,omitempty"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt  time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// Deal represents a listing priced below what the card is worth
+type Deal struct {
+	Listing         Listing            `json:"listing"`
+	CardID          primitive.ObjectID `json:"card_id"`
+	CardName        string             `json:"card_name"`
+	Set             string             `json:"set"`
+	Game            string             `json:"game"`
+	ImageURL        string             `json:"image_url"`
+	Type            string             `json:"type"`                       // "fair_value" or "arbitrage"
+	Cost            float64            `json:"cost"`                       // Listing price plus estimated shipping
+	Reference       float64            `json:"reference"`                  // Fair value, or net proceeds of reselling at the cheapest comparable ask
+	ReferenceSource string             `json:"reference_source,omitempty"` // Marketplace of the comparable ask (arbitrage only)
+	Savings         float64            `json:"savings"`                    // Reference minus cost
+	Discount        float64            `json:"discount"`                   // Percentage below the reference
+	Confidence      float64            `json:"confidence,omitempty"`       // Fair value confidence (fair_value only)
+}
+
+// SparklinePoint represents a daily close in a compact trend line
+type SparklinePoint struct {
+	Date  time.Time `bson:"date" json:"date"`
+	Close float64   `bson:"close" json:"close"`
+}
+
+// LeaderboardEntry represents a ranked card with its move over the window
+type LeaderboardEntry struct {
+	Rank        int                `json:"rank"`
+	CardID      primitive.ObjectID `json:"card_id"`
+	Name        string             `json:"name"`
+	Set         string             `json:"set"`
+	Game        string             `json:"game"`
+	Category    string             `json:"category"`
+	ImageURL    string             `json:"image_url"`
+	StartPrice  float64            `json:"start_price"`
+	EndPrice    float64            `json:"end_price"`
+	Change      float64            `json:"change"`       // Percentage
+	ChangeValue float64            `json:"change_value"` // Dollar amount
+	Volume      int                `json:"volume"`
+	Sparkline   []SparklinePoint   `json:"sparkline"`
+}
+
+// Leaderboards represents the ranked gainers, losers and most traded cards
+type Leaderboards struct {
+	Window      string             `json:"window"` // "24h", "7d", "30d"
+	Game        string             `json:"game,omitempty"`
+	Category    string             `json:"category,omitempty"`
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	Gainers     []LeaderboardEntry `json:"gainers"`
+	Losers      []LeaderboardEntry `json:"losers"`
+	MostTraded  []LeaderboardEntry `json:"most_traded"`
+	GeneratedAt time.Time          `json:"generated_at"`
 }
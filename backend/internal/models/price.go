@@ -8,13 +8,27 @@ import (
 
 // PricePoint represents a single price data point
 type PricePoint struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	CardID    primitive.ObjectID `bson:"card_id" json:"card_id"`
-	Price     float64            `bson:"price" json:"price"`
-	Volume    int                `bson:"volume,omitempty" json:"volume,omitempty"`
-	Source    string             `bson:"source" json:"source"` // "ebay", "tcgplayer"
-	Timestamp time.Time          `bson:"timestamp" json:"timestamp"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ID         primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	CardID     primitive.ObjectID  `bson:"card_id" json:"card_id"`
+	VariantID  *primitive.ObjectID `bson:"variant_id,omitempty" json:"variant_id,omitempty"`
+	Price      float64             `bson:"price" json:"price"`
+	Currency   string              `bson:"currency,omitempty" json:"currency,omitempty"` // ISO 4217; empty means USD
+	Volume     int                 `bson:"volume,omitempty" json:"volume,omitempty"`
+	Source     string              `bson:"source" json:"source"`                               // "ebay", "tcgplayer"
+	ExternalID string              `bson:"external_id,omitempty" json:"external_id,omitempty"` // Marketplace sale ID of ingested sales, unique per source
+	Timestamp  time.Time           `bson:"timestamp" json:"timestamp"`
+	CreatedAt  time.Time           `bson:"created_at" json:"created_at"`
+	Condition  string              `bson:"condition,omitempty" json:"condition,omitempty"` // Same values as Listing.Condition; empty means Near Mint
+	Grader     string              `bson:"grader,omitempty" json:"grader,omitempty"`       // "PSA", "BGS", "CGC"; empty for raw copies
+	Grade      float64             `bson:"grade,omitempty" json:"grade,omitempty"`         // 1-10 in half steps
+
+	// Estimated marks a price converted from another condition (never stored)
+	Estimated bool `bson:"-" json:"estimated,omitempty"`
+
+	// Set by the outlier detector; flagged sales are kept but excluded from
+	// aggregates and indicators unless explicitly requested
+	Outlier      bool    `bson:"outlier,omitempty" json:"outlier,omitempty"`
+	OutlierScore float64 `bson:"outlier_score,omitempty" json:"outlier_score,omitempty"` // Robust z-score vs. recent same-source sales
 }
 
 // PriceHistory represents historical price data with indicators
@@ -24,6 +38,45 @@ type PriceHistory struct {
 	Indicators map[string][]IndicatorPoint `json:"indicators,omitempty"`
 }
 
+// DownsampleInfo describes how a price response was reduced for chart rendering
+type DownsampleInfo struct {
+	Applied            bool   `json:"applied"`
+	Method             string `json:"method"`
+	MaxPoints          int    `json:"max_points"`
+	OriginalPrices     int    `json:"original_prices"`
+	OriginalMarketData int    `json:"original_market_data"`
+	OriginalCandles    int    `json:"original_candles,omitempty"`
+}
+
+// ConditionMultiplier represents a condition's typical price relative to Near Mint for a game
+type ConditionMultiplier struct {
+	Game       string    `bson:"game" json:"game"`
+	Condition  string    `bson:"condition" json:"condition"`
+	Multiplier float64   `bson:"multiplier" json:"multiplier"`
+	Samples    int       `bson:"samples" json:"samples"` // Cards the observed ratio is based on
+	Basis      string    `bson:"basis" json:"basis"`     // "sales", "listings", "default" or "reference" (Near Mint)
+	UpdatedAt  time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// ConditionEstimate describes how a condition-specific price series was built
+type ConditionEstimate struct {
+	Condition      string  `json:"condition"`
+	Estimated      bool    `json:"estimated"` // True when other conditions were converted to fill a sparse series
+	ObservedPoints int     `json:"observed_points"`
+	Multiplier     float64 `json:"multiplier"`
+	Basis          string  `json:"basis"`
+}
+
+// FXRate represents the value of one US dollar in another currency on a given day
+type FXRate struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Date      time.Time          `bson:"date" json:"date"`
+	Currency  string             `bson:"currency" json:"currency"`                 // ISO 4217, e.g. "EUR"
+	Rate      float64            `bson:"rate" json:"rate"`                         // Units of Currency per USD
+	Source    string             `bson:"source,omitempty" json:"source,omitempty"` // Rates file name or "admin"
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
 // IndicatorPoint represents a calculated indicator value
 type IndicatorPoint struct {
 	Timestamp time.Time `json:"timestamp"`
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SealedContents represents what a sealed product contains
+type SealedContents struct {
+	Set         string     `bson:"set,omitempty" json:"set,omitempty"` // Set the singles come from; defaults to the product's Set
+	PacksPerBox int        `bson:"packs_per_box" json:"packs_per_box"`
+	Slots       []PackSlot `bson:"slots" json:"slots"`
+	UpdatedAt   time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// PackSlot represents a group of cards in every pack drawn from the same rarity odds
+type PackSlot struct {
+	Name      string     `bson:"name" json:"name"`   // e.g. "common", "reverse holo", "rare"
+	Count     int        `bson:"count" json:"count"` // Cards per pack in this slot
+	PullRates []PullRate `bson:"pull_rates" json:"pull_rates"`
+}
+
+// PullRate represents the chance a slot card is of a given rarity
+type PullRate struct {
+	Rarity string  `bson:"rarity" json:"rarity"` // Matches Card.Rarity of the singles
+	Rate   float64 `bson:"rate" json:"rate"`     // 0-1; a slot's rates sum to at most 1
+}
+
+// RarityValue represents one rarity's contribution to a sealed product's expected value
+type RarityValue struct {
+	Rarity      string  `json:"rarity"`
+	Cards       int     `json:"cards"`         // Priced singles of this rarity in the set
+	AvgPrice    float64 `json:"avg_price"`     // Each card of a rarity is equally likely
+	PullsPerBox float64 `json:"pulls_per_box"` // Expected cards of this rarity per product
+	Value       float64 `json:"value"`
+}
+
+// EVDriver represents a single's contribution to a sealed product's expected value
+type EVDriver struct {
+	CardID      primitive.ObjectID `json:"card_id"`
+	Name        string             `json:"name"`
+	Rarity      string             `json:"rarity"`
+	Price       float64            `json:"price"`
+	PullsPerBox float64            `json:"pulls_per_box"` // Expected copies per product
+	Value       float64            `json:"value"`
+	Share       float64            `json:"share"` // Percentage of expected value
+}
+
+// ExpectedValue represents the value of opening a sealed product at current single prices
+type ExpectedValue struct {
+	CardID           primitive.ObjectID `json:"card_id"`
+	Set              string             `json:"set"`
+	ProductPrice     float64            `json:"product_price"`
+	PacksPerBox      int                `json:"packs_per_box"`
+	PackValue        float64            `json:"pack_value"`
+	ExpectedValue    float64            `json:"expected_value"`
+	Ratio            *float64           `json:"ratio"` // Expected value / product price; null without a price
+	Rarities         []RarityValue      `json:"rarities"`
+	Drivers          []EVDriver         `json:"drivers"`
+	UnpricedRarities []string           `json:"unpriced_rarities,omitempty"` // Pulled rarities without priced singles, valued at 0
+	Currency         string             `json:"currency,omitempty"`
+}
@@ -0,0 +1,153 @@
+package movers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// MinSparklineDays keeps sparklines readable for short windows
+const MinSparklineDays = 7
+
+// LeaderboardFilter narrows a leaderboard to part of the catalog
+type LeaderboardFilter struct {
+	Game      string
+	Category  string
+	MinVolume int // Applies to gainers and losers, not the most traded list
+	Limit     int // Rows per list
+}
+
+// Leaderboard ranks cards by change and by volume over the window. Moves are
+// measured and ranked exactly like the published movers (Scan and Rank);
+// game and category filters narrow the scan to matching cards first.
+func Leaderboard(ctx context.Context, db *mongo.Database, window Window, now time.Time, filter LeaderboardFilter) (models.Leaderboards, error) {
+	var cardIDs []primitive.ObjectID
+	if filter.Game != "" || filter.Category != "" {
+		var err error
+		if cardIDs, err = catalogSlice(ctx, db, filter); err != nil {
+			return models.Leaderboards{}, err
+		}
+	}
+
+	moves, err := Scan(ctx, db, window, now, cardIDs)
+	if err != nil {
+		return models.Leaderboards{}, err
+	}
+	gainers, losers := Rank(moves, filter.MinVolume, filter.Limit)
+	mostTraded := MostTraded(moves, filter.Limit)
+
+	listed := make([]Move, 0, len(gainers)+len(losers)+len(mostTraded))
+	listed = append(append(append(listed, gainers...), losers...), mostTraded...)
+	cards, err := loadCards(ctx, db, listed)
+	if err != nil {
+		return models.Leaderboards{}, err
+	}
+	sparklines, err := loadSparklines(ctx, db, listed, window, now)
+	if err != nil {
+		return models.Leaderboards{}, err
+	}
+
+	entries := func(ranked []Move) []models.LeaderboardEntry {
+		rows := make([]models.LeaderboardEntry, 0, len(ranked))
+		for _, m := range ranked {
+			card := cards[m.CardID]
+			rows = append(rows, models.LeaderboardEntry{
+				Rank:        len(rows) + 1,
+				CardID:      m.CardID,
+				Name:        card.Name,
+				Set:         card.Set,
+				Game:        card.Game,
+				Category:    card.Category,
+				ImageURL:    card.ImageURL,
+				StartPrice:  m.StartPrice,
+				EndPrice:    m.EndPrice,
+				Change:      m.Change,
+				ChangeValue: m.ChangeValue,
+				Volume:      m.Volume,
+				Sparkline:   sparklines[m.CardID],
+			})
+		}
+		return rows
+	}
+
+	return models.Leaderboards{
+		Window:      window.Label,
+		Game:        filter.Game,
+		Category:    filter.Category,
+		From:        window.Since(now),
+		To:          now,
+		Gainers:     entries(gainers),
+		Losers:      entries(losers),
+		MostTraded:  entries(mostTraded),
+		GeneratedAt: time.Now().UTC(),
+	}, nil
+}
+
+// catalogSlice returns the IDs of the cards matching the filter's game and category
+func catalogSlice(ctx context.Context, db *mongo.Database, filter LeaderboardFilter) ([]primitive.ObjectID, error) {
+	match := bson.M{}
+	if filter.Game != "" {
+		match["game"] = filter.Game
+	}
+	if filter.Category != "" {
+		match["category"] = filter.Category
+	}
+
+	values, err := db.Collection("cards").Distinct(ctx, "_id", match)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leaderboard cards: %v", err)
+	}
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// loadSparklines returns the daily closes behind each move, reaching back at
+// least MinSparklineDays so short windows still show a trend
+func loadSparklines(ctx context.Context, db *mongo.Database, moves []Move, window Window, now time.Time) (map[primitive.ObjectID][]models.SparklinePoint, error) {
+	sparklines := make(map[primitive.ObjectID][]models.SparklinePoint, len(moves))
+	if len(moves) == 0 {
+		return sparklines, nil
+	}
+
+	since := window.Since(now)
+	if window.Days < MinSparklineDays {
+		since = Window{Days: MinSparklineDays}.Since(now)
+	}
+	ids := make([]primitive.ObjectID, len(moves))
+	for i, m := range moves {
+		ids[i] = m.CardID
+	}
+
+	cursor, err := db.Collection("market_data").Find(ctx, bson.M{
+		"card_id":     bson.M{"$in": ids},
+		"date":        bson.M{"$gte": since},
+		"close_price": bson.M{"$gt": 0},
+	}, options.Find().
+		SetSort(bson.M{"date": 1}).
+		SetProjection(bson.M{"card_id": 1, "date": 1, "close_price": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sparklines: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var closes []models.MarketData
+	if err := cursor.All(ctx, &closes); err != nil {
+		return nil, fmt.Errorf("failed to decode sparklines: %v", err)
+	}
+	for _, d := range closes {
+		sparklines[d.CardID] = append(sparklines[d.CardID], models.SparklinePoint{Date: d.Date, Close: d.ClosePrice})
+	}
+	return sparklines, nil
+}
@@ -0,0 +1,168 @@
+package movers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/jamesc159/monmetrics/internal/aggregator"
+)
+
+// Window is a lookback period measured in whole days of market data
+type Window struct {
+	Label string `json:"label"` // As configured, e.g. "24h" or "7d"
+	Days  int    `json:"days"`
+}
+
+// ParseWindow parses a window such as "24h", "7d" or "30d". Hour windows
+// must be whole days since market data is daily.
+func ParseWindow(raw string) (Window, error) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) < 2 {
+		return Window{}, fmt.Errorf("invalid window %q: expected e.g. 24h, 7d or 30d", raw)
+	}
+
+	n, err := strconv.Atoi(raw[:len(raw)-1])
+	if err != nil || n <= 0 {
+		return Window{}, fmt.Errorf("invalid window %q: expected e.g. 24h, 7d or 30d", raw)
+	}
+
+	switch raw[len(raw)-1] {
+	case 'd':
+		return Window{Label: raw, Days: n}, nil
+	case 'h':
+		if n%24 != 0 {
+			return Window{}, fmt.Errorf("invalid window %q: hours must be a multiple of 24", raw)
+		}
+		return Window{Label: raw, Days: n / 24}, nil
+	}
+	return Window{}, fmt.Errorf("invalid window %q: expected e.g. 24h, 7d or 30d", raw)
+}
+
+// ParseWindows parses a comma-separated list of windows
+func ParseWindows(raw string) ([]Window, error) {
+	var windows []Window
+	for _, part := range strings.Split(raw, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		w, err := ParseWindow(part)
+		if err != nil {
+			return nil, err
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// Since returns the first market data day included in the window ending at now.
+// That day's close is the baseline the change is measured from.
+func (w Window) Since(now time.Time) time.Time {
+	return aggregator.TruncateDay(now).AddDate(0, 0, -w.Days)
+}
+
+// Move is a card's price change over a window
+type Move struct {
+	CardID      primitive.ObjectID `bson:"_id" json:"card_id"`
+	StartPrice  float64            `bson:"start_price" json:"start_price"`
+	EndPrice    float64            `bson:"end_price" json:"end_price"`
+	Volume      int                `bson:"volume" json:"volume"`
+	Days        int                `bson:"days" json:"days"`      // Daily candles found in the window
+	Change      float64            `bson:"-" json:"change"`       // Percentage
+	ChangeValue float64            `bson:"-" json:"change_value"` // Dollar amount
+}
+
+// Scan returns every card's move over the window from market_data. cardIDs
+// limits the scan when non-nil. Cards need at least two daily candles.
+func Scan(ctx context.Context, db *mongo.Database, window Window, now time.Time, cardIDs []primitive.ObjectID) ([]Move, error) {
+	match := bson.M{
+		"date":        bson.M{"$gte": window.Since(now)},
+		"close_price": bson.M{"$gt": 0},
+	}
+	if cardIDs != nil {
+		match["card_id"] = bson.M{"$in": cardIDs}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$sort", Value: bson.D{{Key: "card_id", Value: 1}, {Key: "date", Value: 1}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$card_id",
+			"start_price": bson.M{"$first": "$close_price"},
+			"end_price":   bson.M{"$last": "$close_price"},
+			"volume":      bson.M{"$sum": "$volume"},
+			"days":        bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"days": bson.M{"$gte": 2}}}},
+	}
+
+	cursor, err := db.Collection("market_data").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan market data for %s movers: %v", window.Label, err)
+	}
+	defer cursor.Close(ctx)
+
+	moves := make([]Move, 0)
+	if err := cursor.All(ctx, &moves); err != nil {
+		return nil, fmt.Errorf("failed to decode %s movers: %v", window.Label, err)
+	}
+
+	for i := range moves {
+		m := &moves[i]
+		m.ChangeValue = math.Round((m.EndPrice-m.StartPrice)*100) / 100
+		m.Change = math.Round((m.EndPrice-m.StartPrice)/m.StartPrice*10000) / 100
+	}
+	return moves, nil
+}
+
+// Rank returns up to limit gainers (largest rise first) and losers (largest
+// fall first) among moves that traded at least minVolume in the window
+func Rank(moves []Move, minVolume, limit int) (gainers, losers []Move) {
+	gainers, losers = make([]Move, 0), make([]Move, 0)
+	for _, m := range moves {
+		if m.Volume < minVolume {
+			continue
+		}
+		switch {
+		case m.Change > 0:
+			gainers = append(gainers, m)
+		case m.Change < 0:
+			losers = append(losers, m)
+		}
+	}
+
+	sort.SliceStable(gainers, func(i, j int) bool { return gainers[i].Change > gainers[j].Change })
+	sort.SliceStable(losers, func(i, j int) bool { return losers[i].Change < losers[j].Change })
+
+	if limit > 0 && len(gainers) > limit {
+		gainers = gainers[:limit]
+	}
+	if limit > 0 && len(losers) > limit {
+		losers = losers[:limit]
+	}
+	return gainers, losers
+}
+
+// MostTraded returns up to limit moves that traded in the window, highest volume first
+func MostTraded(moves []Move, limit int) []Move {
+	traded := make([]Move, 0)
+	for _, m := range moves {
+		if m.Volume > 0 {
+			traded = append(traded, m)
+		}
+	}
+
+	sort.SliceStable(traded, func(i, j int) bool { return traded[i].Volume > traded[j].Volume })
+	if limit > 0 && len(traded) > limit {
+		traded = traded[:limit]
+	}
+	return traded
+}
@@ -0,0 +1,179 @@
+package movers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// basePriority places computed movers alongside the top of the carousel;
+// each rank below first drops one point
+const basePriority = 100
+
+// Publisher turns the biggest moves into market_mover featured content
+type Publisher struct {
+	db        *mongo.Database
+	windows   []Window
+	minVolume int
+	limit     int
+	ttl       time.Duration
+}
+
+// Result summarizes a publishing run
+type Result struct {
+	Published int `json:"published"`
+	Retired   int `json:"retired"`
+}
+
+// NewPublisher creates a Publisher that features up to limit gainers and
+// losers per window. Entries expire after ttl unless a later run refreshes them.
+func NewPublisher(db *mongo.Database, windows []Window, minVolume, limit int, ttl time.Duration) *Publisher {
+	return &Publisher{db: db, windows: windows, minVolume: minVolume, limit: limit, ttl: ttl}
+}
+
+// Run recomputes movers for every window and upserts them keyed by
+// (card_id, window). Any other market_mover entries, including hand-written
+// ones, are deactivated so the carousel only shows current movers.
+func (p *Publisher) Run(ctx context.Context) (Result, error) {
+	var result Result
+
+	now := time.Now().UTC()
+	expires := now.Add(p.ttl)
+	collection := p.db.Collection("featured_content")
+
+	var writes []mongo.WriteModel
+	var keep []bson.M
+	for _, window := range p.windows {
+		moves, err := Scan(ctx, p.db, window, now, nil)
+		if err != nil {
+			return result, err
+		}
+		gainers, losers := Rank(moves, p.minVolume, p.limit)
+		ranked := append(gainers, losers...)
+		if len(ranked) == 0 {
+			continue
+		}
+
+		cards, err := loadCards(ctx, p.db, ranked)
+		if err != nil {
+			return result, err
+		}
+
+		for i, move := range ranked {
+			card, ok := cards[move.CardID]
+			if !ok {
+				continue
+			}
+
+			rank := i
+			if i >= len(gainers) {
+				rank = i - len(gainers)
+			}
+
+			cardID := move.CardID
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"type": "market_mover", "card_id": cardID, "window": window.Label}).
+				SetUpdate(bson.M{
+					"$set": bson.M{
+						"title":              title(card.Name, move, window),
+						"description":        description(move, window),
+						"image_url":          card.ImageURL,
+						"link":               "/card/" + cardID.Hex(),
+						"priority":           basePriority - rank,
+						"active":             true,
+						"expires_at":         expires,
+						"price_change":       move.Change,
+						"price_change_value": move.ChangeValue,
+					},
+					"$setOnInsert": bson.M{"created_at": now},
+				}).
+				SetUpsert(true))
+			keep = append(keep, bson.M{"card_id": cardID, "window": window.Label})
+		}
+	}
+
+	if len(writes) > 0 {
+		if _, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return result, fmt.Errorf("failed to upsert market movers: %v", err)
+		}
+	}
+	result.Published = len(writes)
+
+	retire := bson.M{"type": "market_mover", "active": true}
+	if len(keep) > 0 {
+		retire["$nor"] = keep
+	}
+	retired, err := collection.UpdateMany(ctx, retire, bson.M{"$set": bson.M{"active": false}})
+	if err != nil {
+		return result, fmt.Errorf("failed to retire stale market movers: %v", err)
+	}
+	result.Retired = int(retired.ModifiedCount)
+
+	return result, nil
+}
+
+// loadCards fetches the cards behind the ranked moves
+func loadCards(ctx context.Context, db *mongo.Database, moves []Move) (map[primitive.ObjectID]models.Card, error) {
+	ids := make([]primitive.ObjectID, len(moves))
+	for i, m := range moves {
+		ids[i] = m.CardID
+	}
+
+	cursor, err := db.Collection("cards").Find(ctx, bson.M{"_id": bson.M{"$in": ids}},
+		options.Find().SetProjection(bson.M{"name": 1, "set": 1, "game": 1, "category": 1, "image_url": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load mover cards: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var cards []models.Card
+	if err := cursor.All(ctx, &cards); err != nil {
+		return nil, fmt.Errorf("failed to decode mover cards: %v", err)
+	}
+
+	byID := make(map[primitive.ObjectID]models.Card, len(cards))
+	for _, c := range cards {
+		byID[c.ID] = c
+	}
+	return byID, nil
+}
+
+// title renders e.g. "Charizard VMAX Up 25.5% This Week"
+func title(name string, move Move, window Window) string {
+	direction := "Up"
+	if move.Change < 0 {
+		direction = "Down"
+	}
+	return fmt.Sprintf("%s %s %.1f%% %s", name, direction, math.Abs(move.Change), period(window))
+}
+
+// description renders the price path behind a move
+func description(move Move, window Window) string {
+	span := fmt.Sprintf("%d days", window.Days)
+	if window.Days == 1 {
+		span = "day"
+	}
+	return fmt.Sprintf("Moved from $%.2f to $%.2f (%+.2f) over the last %s on %d sales",
+		move.StartPrice, move.EndPrice, move.ChangeValue, span, move.Volume)
+}
+
+// period names a window for titles
+func period(window Window) string {
+	switch window.Days {
+	case 1:
+		return "Today"
+	case 7:
+		return "This Week"
+	case 30:
+		return "This Month"
+	}
+	return fmt.Sprintf("in %d Days", window.Days)
+}
@@ -0,0 +1,125 @@
+package outliers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// Detector flags suspect sales (misgrades, bundles, shill bids) in the prices
+// collection. Points are flagged in place and never deleted.
+type Detector struct {
+	db        *mongo.Database
+	window    int
+	threshold float64
+}
+
+// Result summarizes a detection run
+type Result struct {
+	Cards   int `json:"cards"`
+	Scored  int `json:"scored"`
+	Flagged int `json:"flagged"`
+	Changed int `json:"changed"`
+
+	// Dirty holds, per card, the earliest sale whose flag changed so the
+	// affected daily aggregates can be rebuilt
+	Dirty map[primitive.ObjectID]time.Time `json:"-"`
+}
+
+// New creates a new Detector instance
+func New(db *mongo.Database, window int, threshold float64) *Detector {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	return &Detector{db: db, window: window, threshold: threshold}
+}
+
+// Run scores every card that has prices
+func (d *Detector) Run(ctx context.Context) (Result, error) {
+	result := Result{Dirty: make(map[primitive.ObjectID]time.Time)}
+
+	values, err := d.db.Collection("prices").Distinct(ctx, "card_id", bson.M{})
+	if err != nil {
+		return result, fmt.Errorf("failed to list priced cards: %v", err)
+	}
+
+	for _, v := range values {
+		cardID, ok := v.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		if err := d.scoreCard(ctx, cardID, &result); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// ScoreCard scores a single card's full price history
+func (d *Detector) ScoreCard(ctx context.Context, cardID primitive.ObjectID) (Result, error) {
+	result := Result{Dirty: make(map[primitive.ObjectID]time.Time)}
+	err := d.scoreCard(ctx, cardID, &result)
+	return result, err
+}
+
+// scoreCard rescores a card and writes back only the points whose flag or score changed
+func (d *Detector) scoreCard(ctx context.Context, cardID primitive.ObjectID, result *Result) error {
+	opts := options.Find().
+		SetSort(bson.M{"timestamp": 1}).
+		SetProjection(bson.M{"price": 1, "variant_id": 1, "source": 1, "condition": 1, "grader": 1, "grade": 1, "timestamp": 1, "outlier": 1, "outlier_score": 1})
+
+	cursor, err := d.db.Collection("prices").Find(ctx, bson.M{"card_id": cardID}, opts)
+	if err != nil {
+		return fmt.Errorf("failed to load prices for card %s: %v", cardID.Hex(), err)
+	}
+	defer cursor.Close(ctx)
+
+	var prices []models.PricePoint
+	if err := cursor.All(ctx, &prices); err != nil {
+		return fmt.Errorf("failed to decode prices for card %s: %v", cardID.Hex(), err)
+	}
+
+	var writes []mongo.WriteModel
+	for _, s := range ScorePrices(prices, d.window, d.threshold) {
+		p := prices[s.Index]
+		if s.Outlier {
+			result.Flagged++
+		}
+		if p.Outlier == s.Outlier && p.OutlierScore == s.Score {
+			continue
+		}
+
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": p.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"outlier": s.Outlier, "outlier_score": s.Score}}))
+
+		if p.Outlier != s.Outlier {
+			result.Changed++
+			if since, ok := result.Dirty[cardID]; !ok || p.Timestamp.Before(since) {
+				result.Dirty[cardID] = p.Timestamp
+			}
+		}
+	}
+
+	result.Cards++
+	result.Scored += len(prices)
+
+	if len(writes) == 0 {
+		return nil
+	}
+	if _, err := d.db.Collection("prices").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to write outlier flags for card %s: %v", cardID.Hex(), err)
+	}
+	return nil
+}
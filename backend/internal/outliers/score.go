@@ -0,0 +1,122 @@
+package outliers
+
+import (
+	"math"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/jamesc159/monmetrics/internal/conditions"
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+const (
+	// DefaultWindow is how many prior sales of the same source each point is compared to
+	DefaultWindow = 30
+	// DefaultThreshold is the robust z-score beyond which a sale is flagged
+	DefaultThreshold = 3.5
+	// MinHistory is the fewest prior sales needed before a point can be scored
+	MinHistory = 5
+
+	// madScale makes the MAD a consistent estimator of the standard deviation
+	madScale = 1.4826
+	// minRelativeSpread floors the spread at a fraction of the median so runs
+	// of identical prices don't turn every small move into an outlier
+	minRelativeSpread = 0.01
+)
+
+// Score is the outcome of scoring a single price point
+type Score struct {
+	Index   int     // Position in the input slice
+	Score   float64 // Robust z-score; positive above the median, negative below
+	Outlier bool
+}
+
+// ScorePrices scores every point against the rolling median/MAD of the
+// previous window sales of the same variant from the same source, condition
+// and grade (raw copies in each condition and each grader's grade are
+// separate markets). Scores only depend on earlier sales, so appending new
+// points never changes an existing point's score. Points without enough
+// history get a zero score and are never flagged.
+func ScorePrices(prices []models.PricePoint, window int, threshold float64) []Score {
+	if window < MinHistory {
+		window = MinHistory
+	}
+
+	type series struct {
+		variant   primitive.ObjectID
+		source    string
+		condition string
+		grader    string
+		grade     float64
+	}
+	bySeries := make(map[series][]int)
+	for i, p := range prices {
+		key := series{source: p.Source, condition: conditions.Of(p.Condition), grader: p.Grader, grade: p.Grade}
+		if p.VariantID != nil {
+			key.variant = *p.VariantID
+		}
+		bySeries[key] = append(bySeries[key], i)
+	}
+
+	scores := make([]Score, len(prices))
+	for _, indexes := range bySeries {
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return prices[indexes[a]].Timestamp.Before(prices[indexes[b]].Timestamp)
+		})
+
+		for pos, idx := range indexes {
+			scores[idx] = Score{Index: idx}
+			if pos < MinHistory {
+				continue
+			}
+
+			start := pos - window
+			if start < 0 {
+				start = 0
+			}
+			reference := make([]float64, 0, pos-start)
+			for _, j := range indexes[start:pos] {
+				reference = append(reference, prices[j].Price)
+			}
+
+			z := robustZ(prices[idx].Price, reference)
+			scores[idx].Score = math.Round(z*100) / 100
+			scores[idx].Outlier = math.Abs(z) > threshold
+		}
+	}
+
+	return scores
+}
+
+// robustZ returns (value - median) / (1.4826 * MAD) over the reference sample
+func robustZ(value float64, reference []float64) float64 {
+	center := median(reference)
+
+	deviations := make([]float64, len(reference))
+	for i, v := range reference {
+		deviations[i] = math.Abs(v - center)
+	}
+
+	spread := madScale * median(deviations)
+	if floor := minRelativeSpread * math.Abs(center); spread < floor {
+		spread = floor
+	}
+	if spread == 0 {
+		return 0
+	}
+	return (value - center) / spread
+}
+
+// median returns the median of values, which it sorts in place
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
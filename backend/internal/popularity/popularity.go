@@ -0,0 +1,197 @@
+package popularity
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// LookbackMonths is how far back sales and views count toward popularity, and
+// how recently a listing must have been seen to still count as active
+const LookbackMonths = 6
+
+// Weights sets how much each signal contributes to a card's score
+type Weights struct {
+	Sales    float64 `json:"sales"`
+	Listings float64 `json:"listings"`
+	Views    float64 `json:"views"`
+}
+
+// Signals holds a card's raw popularity inputs
+type Signals struct {
+	Sales    int `json:"sales"`    // Units sold in the lookback, outliers excluded
+	Listings int `json:"listings"` // Units in active marketplace listings
+	Views    int `json:"views"`    // Card page views in the lookback
+}
+
+// Ranker recomputes popularity_rank for every card within its game and category
+type Ranker struct {
+	db      *mongo.Database
+	weights Weights
+}
+
+// Result summarizes a ranking run
+type Result struct {
+	Groups  int `json:"groups"`
+	Cards   int `json:"cards"`
+	Changed int `json:"changed"`
+}
+
+// New creates a new Ranker instance
+func New(db *mongo.Database, weights Weights) *Ranker {
+	return &Ranker{db: db, weights: weights}
+}
+
+// Run gathers each signal, scores cards and writes back ranks that moved.
+// Rank 1 is the most popular card in its game and category.
+func (r *Ranker) Run(ctx context.Context) (Result, error) {
+	var result Result
+	since := time.Now().UTC().AddDate(0, -LookbackMonths, 0)
+
+	sales, err := r.sumBy(ctx, "prices", bson.M{
+		"timestamp": bson.M{"$gte": since},
+		"outlier":   bson.M{"$ne": true},
+	}, bson.M{"$max": bson.A{"$volume", 1}}) // Unreported volume still counts as one sale
+	if err != nil {
+		return result, err
+	}
+	// Listings not refreshed in the lookback are stale; unreported quantity counts as one
+	listings, err := r.sumBy(ctx, "listings", bson.M{
+		"updated_at": bson.M{"$gte": since},
+	}, bson.M{"$max": bson.A{"$quantity", 1}})
+	if err != nil {
+		return result, err
+	}
+	views, err := r.sumBy(ctx, "card_views", bson.M{"date": bson.M{"$gte": since}}, "$count")
+	if err != nil {
+		return result, err
+	}
+
+	cursor, err := r.db.Collection("cards").Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{
+		"game": 1, "category": 1, "current_price": 1, "popularity_rank": 1,
+	}))
+	if err != nil {
+		return result, fmt.Errorf("failed to list cards: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var cards []models.Card
+	if err := cursor.All(ctx, &cards); err != nil {
+		return result, fmt.Errorf("failed to decode cards: %v", err)
+	}
+
+	groups := make(map[string][]models.Card)
+	for _, c := range cards {
+		key := c.Game + "|" + c.Category
+		groups[key] = append(groups[key], c)
+	}
+
+	var writes []mongo.WriteModel
+	for _, group := range groups {
+		signals := make([]Signals, len(group))
+		for i, c := range group {
+			signals[i] = Signals{Sales: sales[c.ID], Listings: listings[c.ID], Views: views[c.ID]}
+		}
+
+		for i, idx := range Rank(group, signals, r.weights) {
+			card := group[idx]
+			rank := i + 1
+			if card.PopularityRank == rank {
+				continue
+			}
+			writes = append(writes, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"_id": card.ID}).
+				SetUpdate(bson.M{"$set": bson.M{"popularity_rank": rank}}))
+		}
+
+		result.Groups++
+		result.Cards += len(group)
+	}
+
+	if len(writes) > 0 {
+		if _, err := r.db.Collection("cards").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return result, fmt.Errorf("failed to write popularity ranks: %v", err)
+		}
+	}
+	result.Changed = len(writes)
+
+	return result, nil
+}
+
+// Rank orders one game/category group from most to least popular and returns
+// indexes into cards. Each signal is log-scaled against the group's maximum
+// so one viral card doesn't flatten everyone else, then weighted. Ties go to
+// the more expensive card, matching the catalog's secondary sort.
+func Rank(cards []models.Card, signals []Signals, weights Weights) []int {
+	var maxSales, maxListings, maxViews int
+	for _, s := range signals {
+		maxSales = max(maxSales, s.Sales)
+		maxListings = max(maxListings, s.Listings)
+		maxViews = max(maxViews, s.Views)
+	}
+
+	scores := make([]float64, len(cards))
+	for i, s := range signals {
+		scores[i] = weights.Sales*scaled(s.Sales, maxSales) +
+			weights.Listings*scaled(s.Listings, maxListings) +
+			weights.Views*scaled(s.Views, maxViews)
+	}
+
+	order := make([]int, len(cards))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		i, j := order[a], order[b]
+		if scores[i] != scores[j] {
+			return scores[i] > scores[j]
+		}
+		return cards[i].CurrentPrice > cards[j].CurrentPrice
+	})
+	return order
+}
+
+// scaled maps v onto [0, 1] as log(1+v) / log(1+max)
+func scaled(v, max int) float64 {
+	if max <= 0 {
+		return 0
+	}
+	return math.Log1p(float64(v)) / math.Log1p(float64(max))
+}
+
+// sumBy totals value per card_id over documents in collection matching filter
+func (r *Ranker) sumBy(ctx context.Context, collection string, filter bson.M, value interface{}) (map[primitive.ObjectID]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{"_id": "$card_id", "total": bson.M{"$sum": value}}}},
+	}
+
+	cursor, err := r.db.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate %s: %v", collection, err)
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		CardID primitive.ObjectID `bson:"_id"`
+		Total  int                `bson:"total"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("failed to decode %s totals: %v", collection, err)
+	}
+
+	totals := make(map[primitive.ObjectID]int, len(rows))
+	for _, row := range rows {
+		totals[row.CardID] = row.Total
+	}
+	return totals, nil
+}
@@ -0,0 +1,100 @@
+package popularity
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/aggregator"
+)
+
+// ViewFlushInterval is how often buffered card views are written to card_views
+const ViewFlushInterval = time.Minute
+
+// viewKey identifies one card_views document
+type viewKey struct {
+	cardID primitive.ObjectID
+	date   time.Time
+}
+
+// Views buffers card page views in memory so recording one never waits on the
+// database. Counts are written to card_views in batches by Run and Flush.
+type Views struct {
+	db *mongo.Database
+
+	mu      sync.Mutex
+	pending map[viewKey]int
+}
+
+// NewViews creates a new Views buffer
+func NewViews(db *mongo.Database) *Views {
+	return &Views{db: db, pending: make(map[viewKey]int)}
+}
+
+// Record counts one view of the card toward today's total
+func (v *Views) Record(cardID primitive.ObjectID) {
+	key := viewKey{cardID: cardID, date: aggregator.TruncateDay(time.Now().UTC())}
+
+	v.mu.Lock()
+	v.pending[key]++
+	v.mu.Unlock()
+}
+
+// Run flushes buffered views every interval until ctx is cancelled. Call
+// Flush once more on shutdown to write the views recorded since the last tick.
+func (v *Views) Run(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := v.Flush(ctx); err != nil {
+					log.Printf("⚠️  Could not record card views: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// Flush adds the buffered counts to card_views and returns how many documents
+// it touched. Counts that fail to write go back into the buffer for the next
+// flush.
+func (v *Views) Flush(ctx context.Context) (int, error) {
+	v.mu.Lock()
+	batch := v.pending
+	v.pending = make(map[viewKey]int)
+	v.mu.Unlock()
+
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(batch))
+	for key, count := range batch {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"card_id": key.cardID, "date": key.date}).
+			SetUpdate(bson.M{"$inc": bson.M{"count": count}}).
+			SetUpsert(true))
+	}
+
+	if _, err := v.db.Collection("card_views").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		v.mu.Lock()
+		for key, count := range batch {
+			v.pending[key] += count
+		}
+		v.mu.Unlock()
+		return 0, fmt.Errorf("failed to write card views: %v", err)
+	}
+	return len(writes), nil
+}
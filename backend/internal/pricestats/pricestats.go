@@ -0,0 +1,199 @@
+package pricestats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/aggregator"
+	"github.com/jamesc159/monmetrics/internal/conditions"
+	"github.com/jamesc159/monmetrics/internal/fx"
+	"github.com/jamesc159/monmetrics/internal/models"
+	"github.com/jamesc159/monmetrics/internal/variants"
+)
+
+// Summary holds the price fields denormalized onto each card document
+type Summary struct {
+	CurrentPrice float64   `json:"current_price"`
+	AllTimeHigh  float64   `json:"all_time_high"`
+	AllTimeLow   float64   `json:"all_time_low"`
+	ATHDate      time.Time `json:"ath_date"`
+	ATLDate      time.Time `json:"atl_date"`
+}
+
+// Change records a card whose summary was rewritten
+type Change struct {
+	CardID primitive.ObjectID `json:"card_id"`
+	Name   string             `json:"name"`
+	Before Summary            `json:"before"`
+	After  Summary            `json:"after"`
+}
+
+// Result summarizes a recompute run
+type Result struct {
+	Cards   int      `json:"cards"`
+	Skipped int      `json:"skipped"` // Cards without any usable price history
+	Changed []Change `json:"changed"`
+}
+
+// Updater keeps CurrentPrice and ATH/ATL on cards in sync with the prices collection
+type Updater struct {
+	db *mongo.Database
+}
+
+// New creates a new Updater instance
+func New(db *mongo.Database) *Updater {
+	return &Updater{db: db}
+}
+
+// Summarize derives a card's summary from its sales, sorted oldest first.
+// ATH/ATL are the highest and lowest individual sales; the current price is
+// the volume-weighted average of the most recent trading day, which is less
+// jumpy than the last single sale. ok is false when prices is empty.
+func Summarize(cardID primitive.ObjectID, prices []models.PricePoint) (summary Summary, ok bool) {
+	if len(prices) == 0 {
+		return Summary{}, false
+	}
+
+	summary.AllTimeHigh, summary.ATHDate = prices[0].Price, prices[0].Timestamp
+	summary.AllTimeLow, summary.ATLDate = prices[0].Price, prices[0].Timestamp
+	for _, p := range prices[1:] {
+		// Ties keep the most recent sale
+		if p.Price >= summary.AllTimeHigh {
+			summary.AllTimeHigh, summary.ATHDate = p.Price, p.Timestamp
+		}
+		if p.Price <= summary.AllTimeLow {
+			summary.AllTimeLow, summary.ATLDate = p.Price, p.Timestamp
+		}
+	}
+
+	candles := aggregator.BuildCandles(cardID, prices, aggregator.TruncateDay)
+	summary.CurrentPrice = candles[len(candles)-1].WeightedAvgPrice
+	return summary, true
+}
+
+// Run recomputes every card in the catalog
+func (u *Updater) Run(ctx context.Context) (Result, error) {
+	result := Result{Changed: make([]Change, 0)}
+
+	cursor, err := u.db.Collection("cards").Find(ctx, bson.M{}, options.Find().SetProjection(summaryProjection))
+	if err != nil {
+		return result, fmt.Errorf("failed to list cards: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var cards []models.Card
+	if err := cursor.All(ctx, &cards); err != nil {
+		return result, fmt.Errorf("failed to decode cards: %v", err)
+	}
+
+	for _, card := range cards {
+		change, ok, err := u.update(ctx, card)
+		if err != nil {
+			return result, err
+		}
+		result.Cards++
+		if !ok {
+			result.Skipped++
+			continue
+		}
+		if change != nil {
+			result.Changed = append(result.Changed, *change)
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateCard recomputes a single card, e.g. right after new prices were
+// ingested. It returns nil when nothing changed.
+func (u *Updater) UpdateCard(ctx context.Context, cardID primitive.ObjectID) (*Change, error) {
+	var card models.Card
+	err := u.db.Collection("cards").FindOne(ctx, bson.M{"_id": cardID}, options.FindOne().SetProjection(summaryProjection)).Decode(&card)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load card %s: %v", cardID.Hex(), err)
+	}
+
+	change, _, err := u.update(ctx, card)
+	return change, err
+}
+
+// summaryProjection limits card reads to the fields the updater compares
+var summaryProjection = bson.M{
+	"name": 1, "current_price": 1, "all_time_high": 1, "all_time_low": 1, "ath_date": 1, "atl_date": 1, "variants": 1,
+}
+
+// update recomputes one card from its default variant's raw Near Mint, non-outlier sales and writes the result
+// back when it differs. ok is false when the card has no usable history, in
+// which case the card is left untouched.
+func (u *Updater) update(ctx context.Context, card models.Card) (change *Change, ok bool, err error) {
+	filter := bson.M{
+		"card_id":    card.ID,
+		"variant_id": variants.MatchDefault(card),
+		"outlier":    bson.M{"$ne": true},
+		"grader":     bson.M{"$in": bson.A{nil, ""}},
+		"condition":  conditions.MatchNearMint(),
+	}
+	opts := options.Find().
+		SetSort(bson.M{"timestamp": 1}).
+		SetProjection(bson.M{"price": 1, "currency": 1, "volume": 1, "timestamp": 1})
+
+	cursor, err := u.db.Collection("prices").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load prices for card %s: %v", card.ID.Hex(), err)
+	}
+	defer cursor.Close(ctx)
+
+	var prices []models.PricePoint
+	if err := cursor.All(ctx, &prices); err != nil {
+		return nil, false, fmt.Errorf("failed to decode prices for card %s: %v", card.ID.Hex(), err)
+	}
+	if err := fx.ToBase(ctx, u.db, prices); err != nil {
+		return nil, false, fmt.Errorf("failed to convert prices for card %s: %v", card.ID.Hex(), err)
+	}
+
+	after, ok := Summarize(card.ID, prices)
+	if !ok {
+		return nil, false, nil
+	}
+
+	before := Summary{
+		CurrentPrice: card.CurrentPrice,
+		AllTimeHigh:  card.AllTimeHigh,
+		AllTimeLow:   card.AllTimeLow,
+		ATHDate:      card.ATHDate,
+		ATLDate:      card.ATLDate,
+	}
+	if before.equal(after) {
+		return nil, true, nil
+	}
+
+	update := bson.M{"$set": bson.M{
+		"current_price": after.CurrentPrice,
+		"all_time_high": after.AllTimeHigh,
+		"all_time_low":  after.AllTimeLow,
+		"ath_date":      after.ATHDate,
+		"atl_date":      after.ATLDate,
+		"updated_at":    time.Now().UTC(),
+	}}
+	if _, err := u.db.Collection("cards").UpdateByID(ctx, card.ID, update); err != nil {
+		return nil, true, fmt.Errorf("failed to update card %s: %v", card.ID.Hex(), err)
+	}
+
+	return &Change{CardID: card.ID, Name: card.Name, Before: before, After: after}, true, nil
+}
+
+// equal compares summaries at the precision they are stored with (Mongo
+// dates are millisecond resolution)
+func (s Summary) equal(other Summary) bool {
+	return s.CurrentPrice == other.CurrentPrice &&
+		s.AllTimeHigh == other.AllTimeHigh &&
+		s.AllTimeLow == other.AllTimeLow &&
+		s.ATHDate.Truncate(time.Millisecond).Equal(other.ATHDate.Truncate(time.Millisecond)) &&
+		s.ATLDate.Truncate(time.Millisecond).Equal(other.ATLDate.Truncate(time.Millisecond))
+}
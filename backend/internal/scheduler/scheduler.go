@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a unit of background work run by the scheduler
+type Job func(ctx context.Context) error
+
+// Every runs job immediately and then once per interval until ctx is cancelled.
+// Runs never overlap; a slow run simply delays the next tick.
+func Every(ctx context.Context, name string, interval time.Duration, job Job) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			start := time.Now()
+			if err := job(ctx); err != nil {
+				log.Printf("❌ Job %q failed: %v", name, err)
+			} else {
+				log.Printf("⏱️  Job %q finished in %v", name, time.Since(start))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
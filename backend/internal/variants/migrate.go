@@ -0,0 +1,71 @@
+package variants
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// MigrationResult counts the documents a migration touched
+type MigrationResult struct {
+	Cards    int   `json:"cards"`    // Cards given a default variant
+	Prices   int64 `json:"prices"`   // Price points assigned to a default variant
+	Listings int64 `json:"listings"` // Listings assigned to a default variant
+	Charts   int64 `json:"charts"`   // Saved charts assigned to a default variant
+}
+
+// Migrate gives every card without variants a default variant and assigns
+// the card's unassigned prices, listings and saved charts to its default.
+// It only touches documents without a variant, so it is safe to re-run.
+func Migrate(ctx context.Context, db *mongo.Database) (MigrationResult, error) {
+	var result MigrationResult
+	cardsCollection := db.Collection("cards")
+
+	cursor, err := cardsCollection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"variants": 1}))
+	if err != nil {
+		return result, fmt.Errorf("failed to list cards: %v", err)
+	}
+	defer cursor.Close(ctx)
+
+	var cards []models.Card
+	if err := cursor.All(ctx, &cards); err != nil {
+		return result, fmt.Errorf("failed to decode cards: %v", err)
+	}
+
+	for _, card := range cards {
+		variant, ok := Default(card)
+		if !ok {
+			variant = NewDefault()
+			_, err := cardsCollection.UpdateOne(ctx,
+				bson.M{"_id": card.ID},
+				bson.M{"$push": bson.M{"variants": variant}},
+			)
+			if err != nil {
+				return result, fmt.Errorf("failed to add default variant to card %s: %v", card.ID.Hex(), err)
+			}
+			result.Cards++
+		}
+
+		unassigned := bson.M{"card_id": card.ID, "variant_id": bson.M{"$exists": false}}
+		assign := bson.M{"$set": bson.M{"variant_id": variant.ID}}
+
+		for collection, count := range map[string]*int64{
+			"prices":       &result.Prices,
+			"listings":     &result.Listings,
+			"saved_charts": &result.Charts,
+		} {
+			updated, err := db.Collection(collection).UpdateMany(ctx, unassigned, assign)
+			if err != nil {
+				return result, fmt.Errorf("failed to assign %s of card %s: %v", collection, card.ID.Hex(), err)
+			}
+			*count += updated.ModifiedCount
+		}
+	}
+
+	return result, nil
+}
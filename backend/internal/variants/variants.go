@@ -0,0 +1,61 @@
+package variants
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/jamesc159/monmetrics/internal/models"
+)
+
+// Finishes lists the recognized card finishes
+var Finishes = []string{"normal", "foil", "reverse_holo", "etched"}
+
+// NewDefault returns the variant existing data is assigned to when a card has
+// no printings recorded yet
+func NewDefault() models.CardVariant {
+	return models.CardVariant{
+		ID:        primitive.NewObjectID(),
+		Finish:    "normal",
+		Language:  "en",
+		IsDefault: true,
+	}
+}
+
+// Default returns the card's default variant
+func Default(card models.Card) (models.CardVariant, bool) {
+	for _, v := range card.Variants {
+		if v.IsDefault {
+			return v, true
+		}
+	}
+	return models.CardVariant{}, false
+}
+
+// Find returns the card's variant with the given ID
+func Find(card models.Card, id primitive.ObjectID) (models.CardVariant, bool) {
+	for _, v := range card.Variants {
+		if v.ID == id {
+			return v, true
+		}
+	}
+	return models.CardVariant{}, false
+}
+
+// Match returns the variant_id condition selecting a variant's documents.
+// The default variant also owns documents recorded before variants existed,
+// which have no variant_id.
+func Match(variant models.CardVariant) interface{} {
+	if variant.IsDefault {
+		return bson.M{"$in": bson.A{nil, variant.ID}}
+	}
+	return variant.ID
+}
+
+// MatchDefault returns the variant_id condition for a card's default variant,
+// or for unassigned documents only when the card has no variants
+func MatchDefault(card models.Card) interface{} {
+	if v, ok := Default(card); ok {
+		return Match(v)
+	}
+	return bson.M{"$in": bson.A{nil}}
+}